@@ -9,6 +9,7 @@ import (
 func NewBoyStudent(role *models.Role) *models.Character {
 	character := models.NewCharacter(&models.CharacterData{
 		Name:          "BoyStudent",
+		Tags:          []models.CharacterTag{models.TagStudent, models.TagBoy},
 		StartLocation: models.LocationSchool,
 		GoodwillLimit: 4,
 		ParanoiaLimit: 3,
@@ -16,21 +17,12 @@ func NewBoyStudent(role *models.Role) *models.Character {
 
 	character.GoodwillAbilityList = []*models.CharacterAbilityData{
 		{
-			Name:         "减少同位置学生1点不安",
-			Cost:         2,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
-				location := state.Location(character.CurrentLocation)
-				for _, char := range location.Characters {
-					if !char.ExistsTag("Student") {
-						continue
-					}
-					newParanoia := char.Paranoia() - 1
-					if newParanoia < 0 {
-						newParanoia = 0
-					}
-					char.SetParanoia(newParanoia)
-				}
+			Name:          "减少同位置学生1点不安",
+			Cost:          2,
+			CanBeRefused:  true,
+			TargetOptions: otherStudentsHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetParanoia(target.Paranoia() - 1)
 				return nil
 			},
 		},
@@ -43,6 +35,7 @@ func NewBoyStudent(role *models.Role) *models.Character {
 func NewGirlStudent(role *models.Role) *models.Character {
 	character := models.NewCharacter(&models.CharacterData{
 		Name:          "GirlStudent",
+		Tags:          []models.CharacterTag{models.TagStudent, models.TagGirl},
 		StartLocation: models.LocationSchool,
 		GoodwillLimit: 4,
 		ParanoiaLimit: 3,
@@ -50,18 +43,12 @@ func NewGirlStudent(role *models.Role) *models.Character {
 
 	character.GoodwillAbilityList = []*models.CharacterAbilityData{
 		{
-			Name:         "减少同位置学生1点不安",
-			Cost:         2,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
-				// 护士能力：减少同位置恐慌角色1点不安
-				location := state.Location(character.CurrentLocation)
-				for _, char := range location.Characters {
-					if char != character && char.Paranoia() > 0 {
-						char.SetParanoia(char.Paranoia() - 1)
-						fmt.Printf("护士减少了 %s 1点不安\n", char.Name)
-					}
-				}
+			Name:          "减少同位置学生1点不安",
+			Cost:          2,
+			CanBeRefused:  true,
+			TargetOptions: otherStudentsHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetParanoia(target.Paranoia() - 1)
 				return nil
 			},
 		},
@@ -74,10 +61,12 @@ func NewGirlStudent(role *models.Role) *models.Character {
 func NewRichMansDaughter(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "增加同位置角色1点好感度",
-			Cost:         3,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "增加同位置角色1点好感度",
+			Cost:          3,
+			CanBeRefused:  true,
+			TargetOptions: otherCharactersHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetGoodwill(target.Goodwill() + 1)
 				return nil
 			},
 		},
@@ -85,6 +74,7 @@ func NewRichMansDaughter(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "RichMansDaughter",
+		Tags:                []models.CharacterTag{models.TagStudent, models.TagGirl},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       4,
 		ParanoiaLimit:       3,
@@ -96,10 +86,12 @@ func NewRichMansDaughter(role *models.Role) *models.Character {
 func NewClassRep(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "让领导者取回一张一次性卡牌",
-			Cost:         2,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "让领导者取回一张一次性卡牌",
+			Cost:          2,
+			CanBeRefused:  true,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				// 取回卡牌需要以卡牌为目标，暂无效果
 				return nil
 			},
 		},
@@ -107,6 +99,7 @@ func NewClassRep(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "ClassRep",
+		Tags:                []models.CharacterTag{models.TagStudent, models.TagGirl},
 		StartLocation:       models.LocationSchool,
 		GoodwillLimit:       4,
 		ParanoiaLimit:       3,
@@ -118,17 +111,19 @@ func NewClassRep(role *models.Role) *models.Character {
 func NewMysteryBoy(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "展示自己的角色身份",
-			Cost:         3,
-			CanBeRefused: false,
-			Effect: func(state *models.GameState) error {
-				return nil
+			Name:          "展示自己的角色身份",
+			Cost:          3,
+			CanBeRefused:  false,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				return revealRole(state, target)
 			},
 		},
 	}
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "MysteryBoy",
+		Tags:                []models.CharacterTag{models.TagStudent, models.TagBoy},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       4,
 		ParanoiaLimit:       3,
@@ -140,25 +135,31 @@ func NewMysteryBoy(role *models.Role) *models.Character {
 func NewShrineMaiden(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "减少神社1点阴谋",
-			Cost:         3,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "减少神社1点阴谋",
+			Cost:          3,
+			CanBeRefused:  true,
+			TargetOptions: shrineIfHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetIntrigue(target.Intrigue() - 1)
 				return nil
 			},
 		},
 		{
-			Name:         "展示同位置角色的身份",
-			Cost:         5,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
-				return nil
+			Name:          "展示同位置角色的身份",
+			Cost:          5,
+			CanBeRefused:  true,
+			OncePerLoop:   true,
+			TargetOptions: otherCharactersHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				return revealRole(state, target)
 			},
 		},
 	}
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "ShrineMaiden",
+		Tags:                []models.CharacterTag{models.TagStudent, models.TagGirl},
+		Traits:              []models.CharacterTrait{models.ForbiddenArea(models.LocationCity)},
 		StartLocation:       models.LocationShrine,
 		GoodwillLimit:       6,
 		ParanoiaLimit:       3,
@@ -170,25 +171,40 @@ func NewShrineMaiden(role *models.Role) *models.Character {
 func NewAlien(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "杀死同位置的一个角色",
-			Cost:         4,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
-				return nil
+			Name:          "杀死同位置的一个角色",
+			Cost:          4,
+			CanBeRefused:  true,
+			OncePerLoop:   true,
+			TargetOptions: otherCharactersHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				victim, err := targetCharacter(target)
+				if err != nil {
+					return err
+				}
+				_, err = state.KillCharacter(victim, models.DeathByEffect, "Alien")
+				return err
 			},
 		},
 		{
-			Name:         "复活同位置的一个尸体",
-			Cost:         5,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
-				return nil
+			Name:          "复活同位置的一个尸体",
+			Cost:          5,
+			CanBeRefused:  true,
+			OncePerLoop:   true,
+			TargetOptions: corpsesHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				corpse, err := targetCharacter(target)
+				if err != nil {
+					return err
+				}
+				return corpse.Revive()
 			},
 		},
 	}
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "Alien",
+		Tags:                []models.CharacterTag{models.TagGirl},
+		Traits:              []models.CharacterTrait{models.ForbiddenArea(models.LocationHospital)},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       6,
 		ParanoiaLimit:       3,
@@ -200,18 +216,24 @@ func NewAlien(role *models.Role) *models.Character {
 func NewGodly(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "揭示一个事件的凶手",
-			Cost:         3,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "揭示一个事件的凶手",
+			Cost:          3,
+			CanBeRefused:  true,
+			OncePerLoop:   true,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				// 揭示凶手需要以事件为目标，暂无效果
 				return nil
 			},
 		},
 		{
-			Name:         "减少同位置或角色1点阴谋",
-			Cost:         5,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "减少同位置或角色1点阴谋",
+			Cost:          5,
+			CanBeRefused:  true,
+			OncePerLoop:   true,
+			TargetOptions: locationOrCharactersHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetIntrigue(target.Intrigue() - 1)
 				return nil
 			},
 		},
@@ -219,6 +241,7 @@ func NewGodly(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "Godly",
+		Tags:                []models.CharacterTag{models.TagAdult, models.TagMan},
 		StartLocation:       models.LocationShrine,
 		GoodwillLimit:       6,
 		ParanoiaLimit:       3,
@@ -230,18 +253,24 @@ func NewGodly(role *models.Role) *models.Character {
 func NewPoliceOfficer(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "揭示前一个事件的凶手",
-			Cost:         4,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "揭示前一个事件的凶手",
+			Cost:          4,
+			CanBeRefused:  true,
+			OncePerLoop:   true,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				// 揭示凶手需要以事件为目标，暂无效果
 				return nil
 			},
 		},
 		{
-			Name:         "阻止此处一次死亡",
-			Cost:         5,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "阻止此处一次死亡",
+			Cost:          5,
+			CanBeRefused:  true,
+			OncePerLoop:   true,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				// 阻止死亡需要持续到死亡发生时的效果，暂无效果
 				return nil
 			},
 		},
@@ -249,6 +278,7 @@ func NewPoliceOfficer(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "PoliceOfficer",
+		Tags:                []models.CharacterTag{models.TagAdult, models.TagMan},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       6,
 		ParanoiaLimit:       3,
@@ -260,17 +290,19 @@ func NewPoliceOfficer(role *models.Role) *models.Character {
 func NewOfficeWorker(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "展示自己的角色身份",
-			Cost:         3,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
-				return nil
+			Name:          "展示自己的角色身份",
+			Cost:          3,
+			CanBeRefused:  true,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				return revealRole(state, target)
 			},
 		},
 	}
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "OfficeWorker",
+		Tags:                []models.CharacterTag{models.TagAdult, models.TagMan},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       4,
 		ParanoiaLimit:       3,
@@ -282,10 +314,13 @@ func NewOfficeWorker(role *models.Role) *models.Character {
 func NewInformer(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "揭示副本A或B",
-			Cost:         5,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "揭示副本A或B",
+			Cost:          5,
+			CanBeRefused:  true,
+			OncePerLoop:   true,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				// 揭示副剧本需要以剧本为目标，暂无效果
 				return nil
 			},
 		},
@@ -293,6 +328,7 @@ func NewInformer(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "Informer",
+		Tags:                []models.CharacterTag{models.TagAdult, models.TagWoman},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       6,
 		ParanoiaLimit:       3,
@@ -304,18 +340,22 @@ func NewInformer(role *models.Role) *models.Character {
 func NewPopIdol(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "减少同位置角色1点不安",
-			Cost:         3,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "减少同位置角色1点不安",
+			Cost:          3,
+			CanBeRefused:  true,
+			TargetOptions: otherCharactersHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetParanoia(target.Paranoia() - 1)
 				return nil
 			},
 		},
 		{
-			Name:         "增加同位置角色1点好感度",
-			Cost:         4,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "增加同位置角色1点好感度",
+			Cost:          4,
+			CanBeRefused:  true,
+			TargetOptions: otherCharactersHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetGoodwill(target.Goodwill() + 1)
 				return nil
 			},
 		},
@@ -323,6 +363,7 @@ func NewPopIdol(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "PopIdol",
+		Tags:                []models.CharacterTag{models.TagStudent, models.TagGirl},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       5,
 		ParanoiaLimit:       3,
@@ -334,18 +375,22 @@ func NewPopIdol(role *models.Role) *models.Character {
 func NewJournalist(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "减少任意角色1点不安",
-			Cost:         2,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "减少任意角色1点不安",
+			Cost:          2,
+			CanBeRefused:  true,
+			TargetOptions: livingCharacters,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetParanoia(target.Paranoia() - 1)
 				return nil
 			},
 		},
 		{
-			Name:         "增加同位置或角色1点阴谋",
-			Cost:         2,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "增加同位置或角色1点阴谋",
+			Cost:          2,
+			CanBeRefused:  true,
+			TargetOptions: locationOrCharactersHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetIntrigue(target.Intrigue() + 1)
 				return nil
 			},
 		},
@@ -353,6 +398,7 @@ func NewJournalist(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "Journalist",
+		Tags:                []models.CharacterTag{models.TagAdult, models.TagMan},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       4,
 		ParanoiaLimit:       3,
@@ -364,10 +410,13 @@ func NewJournalist(role *models.Role) *models.Character {
 func NewBoss(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "揭示势力范围内角色的身份",
-			Cost:         5,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "揭示势力范围内角色的身份",
+			Cost:          5,
+			CanBeRefused:  true,
+			OncePerLoop:   true,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				// 势力范围在剧本中尚未定义，暂无效果
 				return nil
 			},
 		},
@@ -375,6 +424,7 @@ func NewBoss(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "Boss",
+		Tags:                []models.CharacterTag{models.TagAdult, models.TagMan},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       6,
 		ParanoiaLimit:       3,
@@ -386,18 +436,28 @@ func NewBoss(role *models.Role) *models.Character {
 func NewDoctor(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "增减同位置角色1点不安",
-			Cost:         2,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "增减同位置角色1点不安",
+			Cost:          2,
+			CanBeRefused:  true,
+			TargetOptions: otherCharactersHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				// 决策只能选择目标，不能选择增减，这里减少不安
+				target.SetParanoia(target.Paranoia() - 1)
 				return nil
 			},
 		},
 		{
-			Name:         "解除病人的位置限制",
-			Cost:         3,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "解除病人的位置限制",
+			Cost:          3,
+			CanBeRefused:  true,
+			TargetOptions: patientAnywhere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				patient, err := targetCharacter(target)
+				if err != nil {
+					return err
+				}
+				// 特征在下个循环开始时重新生效
+				patient.RemoveTraits(state)
 				return nil
 			},
 		},
@@ -405,6 +465,7 @@ func NewDoctor(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "Doctor",
+		Tags:                []models.CharacterTag{models.TagAdult, models.TagMan},
 		StartLocation:       models.LocationHospital,
 		GoodwillLimit:       4,
 		ParanoiaLimit:       3,
@@ -416,6 +477,8 @@ func NewDoctor(role *models.Role) *models.Character {
 func NewPatient(role *models.Role) *models.Character {
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "Patient",
+		Tags:                []models.CharacterTag{models.TagBoy},
+		Traits:              []models.CharacterTrait{models.ForbiddenArea(models.LocationSchool, models.LocationShrine, models.LocationCity)},
 		StartLocation:       models.LocationHospital,
 		GoodwillLimit:       4,
 		ParanoiaLimit:       2,                                // 特殊的不安上限
@@ -427,10 +490,12 @@ func NewPatient(role *models.Role) *models.Character {
 func NewNurse(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "减少同位置恐慌角色1点不安",
-			Cost:         2,
-			CanBeRefused: false,
-			Effect: func(state *models.GameState) error {
+			Name:          "减少同位置恐慌角色1点不安",
+			Cost:          2,
+			CanBeRefused:  false,
+			TargetOptions: panickedCharactersHere,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				target.SetParanoia(target.Paranoia() - 1)
 				return nil
 			},
 		},
@@ -438,6 +503,7 @@ func NewNurse(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "Nurse",
+		Tags:                []models.CharacterTag{models.TagAdult, models.TagWoman},
 		StartLocation:       models.LocationHospital,
 		GoodwillLimit:       4,
 		ParanoiaLimit:       3,
@@ -449,10 +515,12 @@ func NewNurse(role *models.Role) *models.Character {
 func NewHenchman(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "不触发事件",
-			Cost:         3,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
+			Name:          "不触发事件",
+			Cost:          3,
+			CanBeRefused:  true,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				// 阻止事件需要持续到事件阶段的效果，暂无效果
 				return nil
 			},
 		},
@@ -460,6 +528,7 @@ func NewHenchman(role *models.Role) *models.Character {
 
 	return models.NewCharacter(&models.CharacterData{
 		Name:                "Henchman",
+		Tags:                []models.CharacterTag{models.TagAdult, models.TagMan},
 		StartLocation:       models.LocationCity,
 		GoodwillLimit:       4,
 		ParanoiaLimit:       3,
@@ -471,11 +540,12 @@ func NewHenchman(role *models.Role) *models.Character {
 func NewOutsider(role *models.Role) *models.Character {
 	abilities := []*models.CharacterAbilityData{
 		{
-			Name:         "展示自己的身份",
-			Cost:         3,
-			CanBeRefused: true,
-			Effect: func(state *models.GameState) error {
-				return nil
+			Name:          "展示自己的身份",
+			Cost:          3,
+			CanBeRefused:  true,
+			TargetOptions: selfTarget,
+			Effect: func(state *models.GameState, target models.TargetType) error {
+				return revealRole(state, target)
 			},
 		},
 	}
@@ -488,3 +558,104 @@ func NewOutsider(role *models.Role) *models.Character {
 		GoodwillAbilityList: abilities,
 	}, role)
 }
+
+// selfTarget 以角色自身为目标
+func selfTarget(state *models.GameState, source *models.Character) []models.TargetType {
+	return []models.TargetType{source}
+}
+
+// livingCharacters 任意位置的存活角色
+func livingCharacters(state *models.GameState, source *models.Character) []models.TargetType {
+	targets := make([]models.TargetType, 0)
+	for _, char := range state.Characters {
+		if char.IsAlive() {
+			targets = append(targets, char)
+		}
+	}
+	return targets
+}
+
+// charactersHere 与 source 同一位置且满足条件的角色
+func charactersHere(state *models.GameState, source *models.Character, match func(char *models.Character) bool) []models.TargetType {
+	targets := make([]models.TargetType, 0)
+	for _, char := range state.Characters {
+		if char.IsAtLocation(source.Location()) && match(char) {
+			targets = append(targets, char)
+		}
+	}
+	return targets
+}
+
+// otherCharactersHere 同一位置的其他存活角色
+func otherCharactersHere(state *models.GameState, source *models.Character) []models.TargetType {
+	return charactersHere(state, source, func(char *models.Character) bool {
+		return char != source && char.IsAlive()
+	})
+}
+
+// otherStudentsHere 同一位置的其他存活学生
+func otherStudentsHere(state *models.GameState, source *models.Character) []models.TargetType {
+	return charactersHere(state, source, func(char *models.Character) bool {
+		return char != source && char.IsAlive() && char.HasTag(models.TagStudent)
+	})
+}
+
+// panickedCharactersHere 同一位置不安值达到上限的存活角色
+func panickedCharactersHere(state *models.GameState, source *models.Character) []models.TargetType {
+	return charactersHere(state, source, func(char *models.Character) bool {
+		return char.IsAlive() && char.HasReachedParanoiaLimit()
+	})
+}
+
+// corpsesHere 同一位置的尸体
+func corpsesHere(state *models.GameState, source *models.Character) []models.TargetType {
+	return charactersHere(state, source, func(char *models.Character) bool {
+		return !char.IsAlive()
+	})
+}
+
+// locationOrCharactersHere 所在位置及同一位置的其他存活角色
+func locationOrCharactersHere(state *models.GameState, source *models.Character) []models.TargetType {
+	targets := make([]models.TargetType, 0)
+	if location := state.Location(source.Location()); location != nil {
+		targets = append(targets, location)
+	}
+	return append(targets, otherCharactersHere(state, source)...)
+}
+
+// shrineIfHere 角色在神社时以神社为目标
+func shrineIfHere(state *models.GameState, source *models.Character) []models.TargetType {
+	shrine := state.Location(models.LocationShrine)
+	if shrine == nil || !source.IsAtLocation(models.LocationShrine) {
+		return []models.TargetType{}
+	}
+	return []models.TargetType{shrine}
+}
+
+// patientAnywhere 任意位置的存活病人
+func patientAnywhere(state *models.GameState, source *models.Character) []models.TargetType {
+	targets := make([]models.TargetType, 0)
+	if patient := state.Character("Patient"); patient != nil && patient.IsAlive() {
+		targets = append(targets, patient)
+	}
+	return targets
+}
+
+// targetCharacter 能力目标必须是角色
+func targetCharacter(target models.TargetType) (*models.Character, error) {
+	char, ok := target.(*models.Character)
+	if !ok {
+		return nil, fmt.Errorf("ability target %s is not a character", models.TargetName(target))
+	}
+	return char, nil
+}
+
+// revealRole 公开目标角色的身份
+func revealRole(state *models.GameState, target models.TargetType) error {
+	char, err := targetCharacter(target)
+	if err != nil {
+		return err
+	}
+	state.RevealRole(char.Name)
+	return nil
+}
@@ -0,0 +1,148 @@
+package first_steps
+
+import (
+	"testing"
+	"tragedy-looper/engine/internal/models"
+)
+
+func TestGoodwillAbilities(t *testing.T) {
+	shrine := string(models.LocationShrine)
+
+	tests := []struct {
+		name   string
+		source models.CharacterName
+		// ability 角色好感度能力列表中的序号
+		ability int
+		setup   func(f *roleFixture)
+		// targets 可选目标
+		targets []string
+		// target 发动时的目标，为空时不发动
+		target string
+		check  func(t *testing.T, f *roleFixture)
+	}{
+		{
+			name:   "boy student calms the chosen student only",
+			source: "BoyStudent",
+			setup: func(f *roleFixture) {
+				f.move("ShrineMaiden", models.LocationSchool)
+				f.character("ShrineMaiden").SetParanoia(1)
+			},
+			targets: []string{"GirlStudent", "ShrineMaiden"},
+			target:  "ShrineMaiden",
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.character("ShrineMaiden").Paranoia(); got != 0 {
+					t.Errorf("ShrineMaiden paranoia = %d, want 0", got)
+				}
+				if got := f.character("GirlStudent").Paranoia(); got != 2 {
+					t.Errorf("GirlStudent paranoia = %d, want 2", got)
+				}
+			},
+		},
+		{
+			name:    "girl student calms the chosen student only",
+			source:  "GirlStudent",
+			targets: []string{"BoyStudent"},
+			target:  "BoyStudent",
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.character("BoyStudent").Paranoia(); got != 1 {
+					t.Errorf("BoyStudent paranoia = %d, want 1", got)
+				}
+				if got := f.character("GirlStudent").Paranoia(); got != 2 {
+					t.Errorf("GirlStudent paranoia = %d, want 2", got)
+				}
+			},
+		},
+		{
+			name:    "girl student has no target when alone",
+			source:  "GirlStudent",
+			setup:   func(f *roleFixture) { f.move("BoyStudent", models.LocationCity) },
+			targets: []string{},
+		},
+		{
+			name:    "shrine maiden removes intrigue from the shrine",
+			source:  "ShrineMaiden",
+			setup:   func(f *roleFixture) { f.gs.Location(models.LocationShrine).SetIntrigue(2) },
+			targets: []string{shrine},
+			target:  shrine,
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.gs.Location(models.LocationShrine).Intrigue(); got != 1 {
+					t.Errorf("shrine intrigue = %d, want 1", got)
+				}
+			},
+		},
+		{
+			name:    "shrine maiden away from the shrine has no target",
+			source:  "ShrineMaiden",
+			setup:   func(f *roleFixture) { f.move("ShrineMaiden", models.LocationSchool) },
+			targets: []string{},
+		},
+		{
+			name:    "shrine maiden reveals a character in the same location",
+			source:  "ShrineMaiden",
+			ability: 1,
+			setup:   func(f *roleFixture) { f.move("ShrineMaiden", models.LocationSchool) },
+			targets: []string{"BoyStudent", "GirlStudent"},
+			target:  "GirlStudent",
+			check: func(t *testing.T, f *roleFixture) {
+				if !f.gs.RoleRevealed("GirlStudent") || f.gs.RoleRevealed("BoyStudent") {
+					t.Error("only GirlStudent's role should be revealed")
+				}
+			},
+		},
+		{
+			name:    "office worker reveals their own role",
+			source:  "OfficeWorker",
+			targets: []string{"OfficeWorker"},
+			target:  "OfficeWorker",
+			check: func(t *testing.T, f *roleFixture) {
+				if !f.gs.RoleRevealed("OfficeWorker") {
+					t.Error("OfficeWorker's role should be revealed")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRoleFixture(t, nil)
+			for _, name := range []models.CharacterName{"BoyStudent", "GirlStudent"} {
+				f.character(name).SetParanoia(2)
+			}
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			source := f.character(tt.source)
+			ability := source.GoodwillAbilityList[tt.ability]
+
+			names := make([]string, 0)
+			for _, option := range ability.Targets(f.gs, source) {
+				names = append(names, models.TargetName(option))
+			}
+			if !sameNames(names, tt.targets) {
+				t.Fatalf("targets = %v, want %v", names, tt.targets)
+			}
+			if tt.target == "" {
+				return
+			}
+
+			if err := ability.Effect(f.gs, f.target(tt.target)); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, f)
+		})
+	}
+}
+
+func TestGoodwillAbilitiesHaveTargets(t *testing.T) {
+	for _, name := range Set.Characters() {
+		character, ok := Set.NewCharacter(name, NewRole(models.RolePersonType))
+		if !ok {
+			t.Fatalf("unknown character %s", name)
+		}
+		for _, ability := range character.GoodwillAbilityList {
+			if ability.TargetOptions == nil {
+				t.Errorf("%s ability %q has no target options", name, ability.Name)
+			}
+		}
+	}
+}
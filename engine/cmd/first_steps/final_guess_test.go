@@ -0,0 +1,95 @@
+package first_steps
+
+import (
+	"errors"
+	"maps"
+	"testing"
+	"tragedy-looper/engine/internal/models"
+
+	"go.uber.org/zap"
+)
+
+func TestMakeFinalGuess(t *testing.T) {
+	// correct First Steps 第一个剧本的正确答案
+	correct := map[models.CharacterName]models.RoleType{
+		"BoyStudent":    models.RolePersonType,
+		"GirlStudent":   KeyPerson,
+		"ShrineMaiden":  SerialKiller,
+		"PoliceOfficer": ConspiracyTheorist,
+		"OfficeWorker":  Killer,
+		"Doctor":        Brain,
+	}
+
+	tests := []struct {
+		name string
+		// modify 修改正确答案
+		modify func(guess map[models.CharacterName]models.RoleType)
+		err    error
+		// wrong 猜错的角色
+		wrong []models.CharacterName
+	}{
+		{name: "every role correct", modify: func(guess map[models.CharacterName]models.RoleType) {}},
+		{
+			name:   "one wrong role",
+			modify: func(guess map[models.CharacterName]models.RoleType) { guess["BoyStudent"] = Killer },
+			wrong:  []models.CharacterName{"BoyStudent"},
+		},
+		{
+			name:   "Person for a role holder",
+			modify: func(guess map[models.CharacterName]models.RoleType) { guess["Doctor"] = models.RolePersonType },
+			wrong:  []models.CharacterName{"Doctor"},
+		},
+		{
+			// 不属于剧本剧情的身份不会被拒绝，避免泄露剧情
+			name:   "role of the set outside the script's plots",
+			modify: func(guess map[models.CharacterName]models.RoleType) { guess["GirlStudent"] = Cultist },
+			wrong:  []models.CharacterName{"GirlStudent"},
+		},
+		{
+			name:   "role outside the tragedy set",
+			modify: func(guess map[models.CharacterName]models.RoleType) { guess["GirlStudent"] = "Vampire" },
+			err:    models.ErrInvalidGuess,
+		},
+		{
+			name:   "character missing",
+			modify: func(guess map[models.CharacterName]models.RoleType) { delete(guess, "Doctor") },
+			err:    models.ErrInvalidGuess,
+		},
+		{
+			name:   "character not in the script",
+			modify: func(guess map[models.CharacterName]models.RoleType) { guess["Alien"] = models.RolePersonType },
+			err:    models.ErrInvalidGuess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := models.NewGameState(zap.NewNop())
+			gs.Script = NewFirstSteps1()
+			gs.Characters = gs.Script.Characters
+			guess := maps.Clone(correct)
+			tt.modify(guess)
+
+			if err := models.ValidateGuess(gs, guess); !errors.Is(err, tt.err) {
+				t.Fatalf("ValidateGuess err = %v, want %v", err, tt.err)
+			}
+			outcome, err := models.Protagonists{}.MakeFinalGuess(gs, guess)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("MakeFinalGuess err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if outcome.Correct != len(correct)-len(tt.wrong) || outcome.Win != (len(tt.wrong) == 0) {
+				t.Errorf("outcome: %d correct, win %v", outcome.Correct, outcome.Win)
+			}
+			for _, name := range tt.wrong {
+				result := outcome.Result(name)
+				if result == nil || result.Correct || result.Guessed != guess[name] || result.Actual != correct[name] {
+					t.Errorf("result for %s = %+v", name, result)
+				}
+			}
+		})
+	}
+}
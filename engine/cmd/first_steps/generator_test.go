@@ -0,0 +1,38 @@
+package first_steps
+
+import (
+	"testing"
+	"tragedy-looper/engine/internal/models"
+	"tragedy-looper/engine/internal/scripts"
+)
+
+func TestGeneratedIncidentsAreImplemented(t *testing.T) {
+	implemented := map[models.IncidentType]bool{
+		MurderIncidentType:           true,
+		FarawayMurderIncidentType:    true,
+		SuicideIncidentType:          true,
+		HospitalIncidentType:         true,
+		MissingIncidentType:          true,
+		IncreasingUneaseIncidentType: true,
+		SpreadingIncidentType:        true,
+	}
+	for _, incidentType := range Set.Incidents() {
+		if !implemented[incidentType] {
+			t.Errorf("First Steps registers unimplemented incident %s", incidentType)
+		}
+	}
+
+	for seed := uint64(0); seed < 30; seed++ {
+		for _, difficulty := range scripts.Difficulties() {
+			script, err := scripts.Generate(Set, scripts.GenerateOptions{Seed: seed, Difficulty: difficulty})
+			if err != nil {
+				t.Fatalf("seed %d %s: %v", seed, difficulty, err)
+			}
+			for _, scheduled := range script.Incidents {
+				if !implemented[scheduled.Type()] {
+					t.Errorf("seed %d %s: generated unimplemented incident %s", seed, difficulty, scheduled.Type())
+				}
+			}
+		}
+	}
+}
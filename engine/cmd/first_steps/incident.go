@@ -2,6 +2,7 @@ package first_steps
 
 import (
 	"go.uber.org/zap"
+	"slices"
 	"tragedy-looper/engine/internal/models"
 )
 
@@ -28,10 +29,6 @@ const (
 	SpreadingIncidentType models.IncidentType = "SpreadingIncident"
 )
 
-type MurderEffect struct {
-	CulpritName models.CharacterName
-}
-
 // MurderIncident 谋杀事件：角色主动杀死其他角色
 type MurderIncident struct{}
 
@@ -39,44 +36,29 @@ func (incident *MurderIncident) Type() models.IncidentType {
 	return MurderIncidentType
 }
 
-func (incident *MurderIncident) Execute(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) error {
-	//murderEffect, ok := target.(*MurderEffect)
-	//if !ok {
-	//	return fmt.Errorf("target is not a MurderEffect %s", tools.GetInterfaceType(target))
-	//}
-	//if murderEffect.CulpritName == "" {
-	//	return fmt.Errorf("culprit is nil")
-	//}
-	//
-	//gameState.KillCharacter(murderEffect.CulpritName)
-	return nil
+func (incident *MurderIncident) Execute(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) error {
+	victim, ok := target.Target.(*models.Character)
+	if !ok {
+		// 同一位置没有其他角色时事件照常发生但没有效果
+		return nil
+	}
+	_, err := gameState.KillCharacter(victim, models.DeathByIncident, string(target.Culprit.Name))
+	return err
 }
 
-func (incident *MurderIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) bool {
-	//murderEffect, ok := target.(*MurderEffect)
-	//if !ok {
-	//	logger.DPanic("target is not a MurderEffect",
-	//		zap.Error(fmt.Errorf("target is not a MurderEffect %s", tools.GetInterfaceType(target))))
-	//	return false
-	//}
-	//if murderEffect.CulpritName == "" {
-	//	logger.DPanic("culprit is nil")
-	//	return false
-	//}
-	//
-	//culpritCharacter := gameState.GetCharacter(murderEffect.CulpritName)
-	//if culpritCharacter == nil {
-	//	logger.DPanic("culprit is nil")
-	//	return false
-	//}
-	//
-	//return culpritCharacter.IsAlive() && culpritCharacter.IsParanoia()
-	return false
+func (incident *MurderIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) bool {
+	return target.Culprit != nil
 }
 
-// FarawayMurderEffect 远程谋杀效果
-type FarawayMurderEffect struct {
-	TargetName models.CharacterName
+// TargetOptions 与当事人同一位置的其他存活角色
+func (incident *MurderIncident) TargetOptions(gameState *models.GameState, culprit *models.Character) []models.TargetType {
+	options := make([]models.TargetType, 0)
+	for _, char := range gameState.Characters {
+		if char != culprit && char.IsAlive() && char.IsAtLocation(culprit.Location()) {
+			options = append(options, char)
+		}
+	}
+	return options
 }
 
 // FarawayMurderIncident 远程谋杀：带有至少2个阴谋值的角色死亡
@@ -86,45 +68,29 @@ func (incident *FarawayMurderIncident) Type() models.IncidentType {
 	return FarawayMurderIncidentType
 }
 
-func (incident *FarawayMurderIncident) Execute(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) error {
-	//effect, ok := target.(*FarawayMurderEffect)
-	//if !ok {
-	//	return fmt.Errorf("target is not a FarawayMurderEffect %s", tools.GetInterfaceType(target))
-	//}
-	//
-	//// 确保目标角色存在
-	//if effect.TargetName == "" {
-	//	return fmt.Errorf("target character is nil")
-	//}
-	//
-	//// 执行远程谋杀
-	//gameState.KillCharacter(effect.TargetName)
-	return nil
+func (incident *FarawayMurderIncident) Execute(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) error {
+	victim, ok := target.Target.(*models.Character)
+	if !ok {
+		// 没有带有至少2个阴谋值的角色时事件照常发生但没有效果
+		return nil
+	}
+	_, err := gameState.KillCharacter(victim, models.DeathByIncident, string(target.Culprit.Name))
+	return err
 }
 
-func (incident *FarawayMurderIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) bool {
-	//effect, ok := target.(*FarawayMurderEffect)
-	//if !ok {
-	//	logger.DPanic("target is not a FarawayMurderEffect",
-	//		zap.Error(fmt.Errorf("invalid target type %s", tools.GetInterfaceType(target))))
-	//	return false
-	//}
-	//
-	//// 获取目标角色
-	//targetCharacter := gameState.GetCharacter(effect.TargetName)
-	//if targetCharacter == nil {
-	//	logger.DPanic("target character not found")
-	//	return false
-	//}
-	//
-	//// 检查目标是否活着且阴谋值至少为2
-	//return targetCharacter.IsAlive() && targetCharacter.GetIntrigue() >= 2
-	return false
+func (incident *FarawayMurderIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) bool {
+	return target.Culprit != nil
 }
 
-// SuicideEffect 自杀事件效果
-type SuicideEffect struct {
-	CharacterName models.CharacterName
+// TargetOptions 任意位置上带有至少2个阴谋值的存活角色
+func (incident *FarawayMurderIncident) TargetOptions(gameState *models.GameState, culprit *models.Character) []models.TargetType {
+	options := make([]models.TargetType, 0)
+	for _, char := range gameState.Characters {
+		if char.IsAlive() && char.Intrigue() >= 2 {
+			options = append(options, char)
+		}
+	}
+	return options
 }
 
 // SuicideIncident 自杀事件：角色因疑神值过高自我了断
@@ -134,30 +100,13 @@ func (incident *SuicideIncident) Type() models.IncidentType {
 	return SuicideIncidentType
 }
 
-func (incident *SuicideIncident) Execute(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) error {
-	//effect, ok := target.(*SuicideEffect)
-	//if !ok {
-	//	return fmt.Errorf("target is not a SuicideEffect %s", tools.GetInterfaceType(target))
-	//}
-	//gameState.KillCharacter(effect.CharacterName)
-	//return nil
-	return nil
-}
-
-func (incident *SuicideIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) bool {
-	//effect, ok := target.(*SuicideEffect)
-	//if !ok {
-	//	logger.DPanic("target is not a SuicideEffect", zap.Error(fmt.Errorf("invalid target type")))
-	//	return false
-	//}
-	//character := gameState.GetCharacter(effect.CharacterName)
-	//return character != nil && character.IsAlive() && character.GetParanoia() >= 4
-	return false
+func (incident *SuicideIncident) Execute(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) error {
+	_, err := gameState.KillCharacter(target.Culprit, models.DeathByIncident, string(SuicideIncidentType))
+	return err
 }
 
-// HospitalIncidentEffect 医院事故效果
-type HospitalIncidentEffect struct {
-	Location models.LocationType
+func (incident *SuicideIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) bool {
+	return target.Culprit != nil
 }
 
 // HospitalIncident 医院事故：阴谋值积累引发的特殊事件
@@ -167,141 +116,150 @@ func (incident *HospitalIncident) Type() models.IncidentType {
 	return HospitalIncidentType
 }
 
-func (incident *HospitalIncident) Execute(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) error {
-	//effect, ok := target.(*HospitalIncidentEffect)
-	//if !ok {
-	//	return fmt.Errorf("target is not a HospitalIncidentEffect %s", tools.GetInterfaceType(target))
-	//}
-	//
-	//location := gameState.GetLocation(effect.Location)
-	//if location.GetIntrigue() >= 2 {
-	//	// 杀死所有主角
-	//	gameState.KillAllProtagonists()
-	//} else if location.GetIntrigue() >= 1 {
-	//	// 杀死医院中的所有角色
-	//	gameState.KillAllCharactersInLocation(effect.Location)
-	//}
-	//return nil
+func (incident *HospitalIncident) Execute(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) error {
+	hospital := gameState.Location(models.LocationHospital)
+	if hospital == nil {
+		return nil
+	}
+	if hospital.Intrigue() >= 1 {
+		// 杀死医院中的所有角色
+		for _, character := range gameState.Characters {
+			if character.IsAlive() && character.IsAtLocation(models.LocationHospital) {
+				if _, err := gameState.KillCharacter(character, models.DeathByIncident, string(HospitalIncidentType)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if hospital.Intrigue() >= 2 {
+		// 主角死亡，循环立即结束
+		gameState.KillProtagonists(string(HospitalIncidentType))
+	}
 	return nil
 }
 
-func (incident *HospitalIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) bool {
-	//effect, ok := target.(*HospitalIncidentEffect)
-	//if !ok {
-	//	logger.DPanic("target is not a HospitalIncidentEffect")
-	//	return false
-	//}
-	//location := gameState.GetLocation(effect.Location)
-	//return location != nil && location.GetIntrigue() > 0
-	return true
+func (incident *HospitalIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) bool {
+	return target.Culprit != nil
 }
 
-// MissingEffect 失踪事件效果
-type MissingEffect struct {
-	CharacterName models.CharacterName
-}
-
-// MissingIncident 人员失踪：角色永久退出当前循环
+// MissingIncident 人员失踪：当事人移动到任意位置，并在该位置放置1个阴谋值
 type MissingIncident struct{}
 
 func (incident *MissingIncident) Type() models.IncidentType {
 	return MissingIncidentType
 }
 
-func (incident *MissingIncident) Execute(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) error {
-	//effect, ok := target.(*MissingEffect)
-	//if !ok {
-	//	return fmt.Errorf("target is not a MissingEffect %s", tools.GetInterfaceType(target))
-	//}
-	//gameState.RemoveCharacter(effect.CharacterName)
-	//return nil
+func (incident *MissingIncident) Execute(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) error {
+	location, ok := target.Target.(*models.Location)
+	if !ok {
+		// 当事人无法移动到任何位置时事件照常发生但没有效果
+		return nil
+	}
+	if location.LocationType != target.Culprit.Location() {
+		if err := gameState.Board.MoveTo(target.Culprit, location.LocationType); err != nil {
+			return err
+		}
+	}
+	location.SetIntrigue(location.Intrigue() + 1)
 	return nil
 }
 
-func (incident *MissingIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) bool {
-	//effect, ok := target.(*MissingEffect)
-	//if !ok {
-	//	logger.DPanic("target is not a MissingEffect")
-	//	return false
-	//}
-	//character := gameState.GetCharacter(effect.CharacterName)
-	//return character != nil && character.IsAlive()
-	return false
+func (incident *MissingIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) bool {
+	return target.Culprit != nil
 }
 
-// IncreasingUneaseEffect 不安扩散效果
-type IncreasingUneaseEffect struct {
-	TargetCharacterName         models.CharacterName
-	IntrigueTargetCharacterName models.CharacterName
+// TargetOptions 当事人所在的位置和可以移动到的位置
+func (incident *MissingIncident) TargetOptions(gameState *models.GameState, culprit *models.Character) []models.TargetType {
+	options := make([]models.TargetType, 0)
+	for _, locType := range gameState.Board.Locations() {
+		location := gameState.Location(locType)
+		if location == nil {
+			continue
+		}
+		if locType == culprit.Location() || culprit.CanMoveTo(locType) {
+			options = append(options, location)
+		}
+	}
+	return options
 }
 
-// IncreasingUneaseIncident 不安扩散：提升指定区域的疑神值
+// IncreasingUneaseIncident 不安扩散：在任意角色上放置2个疑神值，然后在另一个角色上放置1个阴谋值
 type IncreasingUneaseIncident struct{}
 
 func (incident *IncreasingUneaseIncident) Type() models.IncidentType {
 	return IncreasingUneaseIncidentType
 }
 
-func (incident *IncreasingUneaseIncident) Execute(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) error {
-	//effect, ok := target.(*IncreasingUneaseEffect)
-	//if !ok {
-	//	return fmt.Errorf("target is not an IncreasingUneaseEffect %s", tools.GetInterfaceType(target))
-	//}
-	//
-	//targetChar := gameState.GetCharacter(effect.TargetCharacterName)
-	//targetChar.AddParanoia(2)
-	//
-	//intrigueChar := gameState.GetCharacter(effect.IntrigueTargetCharacterName)
-	//intrigueChar.AddIntrigue(1)
+func (incident *IncreasingUneaseIncident) Execute(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) error {
+	// 依次选择的两个角色，没有可选角色时对应的效果不发生
+	if char, ok := incidentTarget(target, 0); ok {
+		char.SetParanoia(char.Paranoia() + 2)
+	}
+	if char, ok := incidentTarget(target, 1); ok {
+		char.SetIntrigue(char.Intrigue() + 1)
+	}
 	return nil
 }
 
-func (incident *IncreasingUneaseIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) bool {
-	//effect, ok := target.(*IncreasingUneaseEffect)
-	//if !ok {
-	//	logger.DPanic("target is not an IncreasingUneaseEffect")
-	//	return false
-	//}
-	//return gameState.GetCharacter(effect.TargetCharacterName) != nil &&
-	//	gameState.GetCharacter(effect.IntrigueTargetCharacterName) != nil
-	return true
+func (incident *IncreasingUneaseIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) bool {
+	return target.Culprit != nil
 }
 
-// SpreadingEffect 阴谋扩散效果
-type SpreadingEffect struct {
-	FromCharacterName models.CharacterName
-	ToCharacterName   models.CharacterName
+func (incident *IncreasingUneaseIncident) TargetCount() int {
+	return 2
 }
 
-// SpreadingIncident 阴谋扩散：提升指定区域的阴谋值
+// NextTargetOptions 任意存活角色，第二个目标不能与第一个相同
+func (incident *IncreasingUneaseIncident) NextTargetOptions(gameState *models.GameState, culprit *models.Character, chosen []models.TargetType) []models.TargetType {
+	return otherLivingCharacters(gameState, chosen)
+}
+
+// SpreadingIncident 阴谋扩散：移除任意角色2个好感值，然后在另一个角色上放置2个好感值
 type SpreadingIncident struct{}
 
 func (incident *SpreadingIncident) Type() models.IncidentType {
 	return SpreadingIncidentType
 }
 
-func (incident *SpreadingIncident) Execute(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) error {
-	//effect, ok := target.(*SpreadingEffect)
-	//if !ok {
-	//	return fmt.Errorf("target is not a SpreadingEffect %s", tools.GetInterfaceType(target))
-	//}
-	//
-	//fromChar := gameState.GetCharacter(effect.FromCharacterName)
-	//fromChar.RemoveGoodwill(2)
-	//
-	//toChar := gameState.GetCharacter(effect.ToCharacterName)
-	//toChar.AddGoodwill(2)
+func (incident *SpreadingIncident) Execute(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) error {
+	if char, ok := incidentTarget(target, 0); ok {
+		char.SetGoodwill(char.Goodwill() - 2)
+	}
+	if char, ok := incidentTarget(target, 1); ok {
+		char.SetGoodwill(char.Goodwill() + 2)
+	}
 	return nil
 }
 
-func (incident *SpreadingIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target models.IncidentEffectTarget) bool {
-	//effect, ok := target.(*SpreadingEffect)
-	//if !ok {
-	//	logger.DPanic("target is not a SpreadingEffect")
-	//	return false
-	//}
-	//fromChar := gameState.GetCharacter(effect.FromCharacterName)
-	//toChar := gameState.GetCharacter(effect.ToCharacterName)
-	//return fromChar != nil && toChar != nil && fromChar.GetGoodwill() >= 2
-	return true
+func (incident *SpreadingIncident) IsTriggerable(logger zap.Logger, gameState *models.GameState, target *models.IncidentContext) bool {
+	return target.Culprit != nil
+}
+
+func (incident *SpreadingIncident) TargetCount() int {
+	return 2
+}
+
+// NextTargetOptions 任意存活角色，第二个目标不能与第一个相同
+func (incident *SpreadingIncident) NextTargetOptions(gameState *models.GameState, culprit *models.Character, chosen []models.TargetType) []models.TargetType {
+	return otherLivingCharacters(gameState, chosen)
+}
+
+// incidentTarget 返回多目标事件第 i 个选择的角色
+func incidentTarget(target *models.IncidentContext, i int) (*models.Character, bool) {
+	if i >= len(target.Targets) {
+		return nil, false
+	}
+	char, ok := target.Targets[i].(*models.Character)
+	return char, ok
+}
+
+// otherLivingCharacters 返回尚未被选择的存活角色
+func otherLivingCharacters(gameState *models.GameState, chosen []models.TargetType) []models.TargetType {
+	options := make([]models.TargetType, 0)
+	for _, char := range gameState.Characters {
+		if char.IsAlive() && !slices.Contains(chosen, models.TargetType(char)) {
+			options = append(options, char)
+		}
+	}
+	return options
 }
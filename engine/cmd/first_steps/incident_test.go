@@ -0,0 +1,218 @@
+package first_steps
+
+import (
+	"slices"
+	"testing"
+	"tragedy-looper/engine/internal/models"
+
+	"go.uber.org/zap"
+)
+
+func TestIncidentTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		incident models.IncidentTargetSelector
+		culprit  models.CharacterName
+		setup    func(f *roleFixture)
+		// targets 可选目标
+		targets []string
+		// target 发生时的目标，为空时没有目标
+		target string
+		check  func(t *testing.T, f *roleFixture)
+	}{
+		{
+			name:     "murder kills a character at the culprit's location",
+			incident: &MurderIncident{},
+			culprit:  "BoyStudent",
+			targets:  []string{"GirlStudent"},
+			target:   "GirlStudent",
+			check: func(t *testing.T, f *roleFixture) {
+				if f.character("GirlStudent").IsAlive() {
+					t.Error("GirlStudent should be dead")
+				}
+			},
+		},
+		{
+			name:     "faraway murder without intrigue has no target",
+			incident: &FarawayMurderIncident{},
+			culprit:  "BoyStudent",
+			targets:  []string{},
+		},
+		{
+			name:     "faraway murder kills a character with 2 intrigue anywhere",
+			incident: &FarawayMurderIncident{},
+			culprit:  "BoyStudent",
+			setup: func(f *roleFixture) {
+				f.character("OfficeWorker").SetIntrigue(2)
+				f.character("GirlStudent").SetIntrigue(1)
+			},
+			targets: []string{"OfficeWorker"},
+			target:  "OfficeWorker",
+			check: func(t *testing.T, f *roleFixture) {
+				if f.character("OfficeWorker").IsAlive() {
+					t.Error("OfficeWorker should be dead")
+				}
+			},
+		},
+		{
+			name:     "missing moves the culprit and adds intrigue to the location",
+			incident: &MissingIncident{},
+			culprit:  "BoyStudent",
+			targets: []string{
+				string(models.LocationCity),
+				string(models.LocationHospital),
+				string(models.LocationSchool),
+				string(models.LocationShrine),
+			},
+			target: string(models.LocationShrine),
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.character("BoyStudent").Location(); got != models.LocationShrine {
+					t.Errorf("BoyStudent at %s, want %s", got, models.LocationShrine)
+				}
+				if got := f.gs.Location(models.LocationShrine).Intrigue(); got != 1 {
+					t.Errorf("shrine intrigue = %d, want 1", got)
+				}
+			},
+		},
+		{
+			name:     "missing skips locations forbidden to the culprit",
+			incident: &MissingIncident{},
+			culprit:  "BoyStudent",
+			setup: func(f *roleFixture) {
+				boy := f.character("BoyStudent")
+				boy.ForbiddenLocations = append(boy.ForbiddenLocations, models.LocationHospital)
+			},
+			targets: []string{
+				string(models.LocationCity),
+				string(models.LocationSchool),
+				string(models.LocationShrine),
+			},
+			target: string(models.LocationSchool),
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.gs.Location(models.LocationSchool).Intrigue(); got != 1 {
+					t.Errorf("school intrigue = %d, want 1", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRoleFixture(t, nil)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			ctx := &models.IncidentContext{Loop: 1, Day: 1, Culprit: f.character(tt.culprit)}
+			if !tt.incident.IsTriggerable(*zap.NewNop(), f.gs, ctx) {
+				t.Fatal("incident should be triggerable")
+			}
+
+			names := make([]string, 0)
+			for _, option := range tt.incident.TargetOptions(f.gs, ctx.Culprit) {
+				names = append(names, models.TargetName(option))
+			}
+			if !sameNames(names, tt.targets) {
+				t.Fatalf("targets = %v, want %v", names, tt.targets)
+			}
+
+			if tt.target != "" {
+				ctx.Target = f.target(tt.target)
+			}
+			if err := tt.incident.Execute(*zap.NewNop(), f.gs, ctx); err != nil {
+				t.Fatal(err)
+			}
+			if tt.check != nil {
+				tt.check(t, f)
+			}
+		})
+	}
+}
+
+func TestMultiTargetIncidents(t *testing.T) {
+	tests := []struct {
+		name     string
+		incident models.IncidentMultiTargetSelector
+		setup    func(f *roleFixture)
+		// targets 依次选择的目标
+		targets []string
+		check   func(t *testing.T, f *roleFixture)
+	}{
+		{
+			name:     "increasing unease adds paranoia then intrigue to another character",
+			incident: &IncreasingUneaseIncident{},
+			targets:  []string{"GirlStudent", "OfficeWorker"},
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.character("GirlStudent").Paranoia(); got != 2 {
+					t.Errorf("GirlStudent paranoia = %d, want 2", got)
+				}
+				if got := f.character("OfficeWorker").Intrigue(); got != 1 {
+					t.Errorf("OfficeWorker intrigue = %d, want 1", got)
+				}
+			},
+		},
+		{
+			name:     "spreading moves goodwill to another character",
+			incident: &SpreadingIncident{},
+			setup: func(f *roleFixture) {
+				f.character("GirlStudent").SetGoodwill(3)
+			},
+			targets: []string{"GirlStudent", "BoyStudent"},
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.character("GirlStudent").Goodwill(); got != 1 {
+					t.Errorf("GirlStudent goodwill = %d, want 1", got)
+				}
+				if got := f.character("BoyStudent").Goodwill(); got != 2 {
+					t.Errorf("BoyStudent goodwill = %d, want 2", got)
+				}
+			},
+		},
+		{
+			name:     "spreading does not remove goodwill below zero",
+			incident: &SpreadingIncident{},
+			setup: func(f *roleFixture) {
+				f.character("GirlStudent").SetGoodwill(1)
+			},
+			targets: []string{"GirlStudent", "BoyStudent"},
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.character("GirlStudent").Goodwill(); got != 0 {
+					t.Errorf("GirlStudent goodwill = %d, want 0", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRoleFixture(t, nil)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			ctx := &models.IncidentContext{Loop: 1, Day: 1, Culprit: f.character("BoyStudent")}
+			if !tt.incident.IsTriggerable(*zap.NewNop(), f.gs, ctx) {
+				t.Fatal("incident should be triggerable")
+			}
+			if got := tt.incident.TargetCount(); got != len(tt.targets) {
+				t.Fatalf("target count = %d, want %d", got, len(tt.targets))
+			}
+
+			for _, name := range tt.targets {
+				target := f.target(name)
+				if !slices.Contains(tt.incident.NextTargetOptions(f.gs, ctx.Culprit, ctx.Targets), target) {
+					t.Fatalf("%s is not a target option after %d choices", name, len(ctx.Targets))
+				}
+				ctx.Targets = append(ctx.Targets, target)
+			}
+			// 已选择的角色不能再次被选择
+			for _, option := range tt.incident.NextTargetOptions(f.gs, ctx.Culprit, ctx.Targets[:1]) {
+				if option == ctx.Targets[0] {
+					t.Errorf("%s offered twice", models.TargetName(option))
+				}
+			}
+
+			if err := tt.incident.Execute(*zap.NewNop(), f.gs, ctx); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, f)
+		})
+	}
+}
@@ -8,20 +8,19 @@ import "tragedy-looper/engine/internal/models"
 type LightOfTheAvengerFailureRule struct{}
 
 func (r *LightOfTheAvengerFailureRule) CheckCondition(gameState *models.GameState) bool {
-	// Find the Brain character
-	//for _, character := range gameState.Characters {
-	//	if character.Role() != nil && character.Role().Type == models.Brain {
-	//		// Check if the Brain's starting location has at least 2 Intrigue counters
-	//		location := character.data.StartingLocation
-	//		intrigueCount := gameState.Locations[location].IntrigueCount
-	//		return intrigueCount >= 2
-	//	}
-	//}
+	// Check if the Brain's starting location has at least 2 Intrigue counters
+	for _, character := range gameState.Characters {
+		if !character.HasRole(Brain) {
+			continue
+		}
+		location := gameState.Location(character.StartLocation)
+		return location != nil && location.Intrigue() >= 2
+	}
 	return false
 }
 
-func (r *LightOfTheAvengerFailureRule) GetTiming() models.DayPhase {
-	return models.PhaseDayEnd
+func (r *LightOfTheAvengerFailureRule) GetTiming() models.RuleTiming {
+	return models.RuleTimingLoopEnd
 }
 
 func (r *LightOfTheAvengerFailureRule) GetRuleType() models.RuleType {
@@ -37,13 +36,12 @@ type APlaceToProtectFailureRule struct{}
 
 func (r *APlaceToProtectFailureRule) CheckCondition(gameState *models.GameState) bool {
 	// Check if the School has at least 2 Intrigue counters
-	//intrigueCount := gameState.Locations[models.LocationSchool].IntrigueCount
-	//return intrigueCount >= 2
-	return false
+	school := gameState.Location(models.LocationSchool)
+	return school != nil && school.Intrigue() >= 2
 }
 
-func (r *APlaceToProtectFailureRule) GetTiming() models.DayPhase {
-	return models.PhaseDayEnd
+func (r *APlaceToProtectFailureRule) GetTiming() models.RuleTiming {
+	return models.RuleTimingLoopEnd
 }
 
 func (r *APlaceToProtectFailureRule) GetRuleType() models.RuleType {
@@ -74,8 +72,8 @@ func (r *AnUnsettlingRumorOptionalRule) Execute(gameState *models.GameState) {
 	//}
 }
 
-func (r *AnUnsettlingRumorOptionalRule) GetTiming() models.DayPhase {
-	return models.PhaseMastermindAbilities
+func (r *AnUnsettlingRumorOptionalRule) GetTiming() models.RuleTiming {
+	return models.RuleTimingMastermindAbilities
 }
 
 func (r *AnUnsettlingRumorOptionalRule) GetRuleType() models.RuleType {
@@ -98,46 +96,49 @@ func init() {
 	// Murder Plan
 	// Source: First Steps Main Plots in your knowledge base
 	murderPlan := models.NewPlot("murder_plan", "Murder Plan", models.MainPlot, "Roles to add: Key Person, Brain, Killer.")
-	murderPlan.AddRequiredRole("KeyPerson", 1)
-	murderPlan.AddRequiredRole("Brain", 1)
-	murderPlan.AddRequiredRole("Killer", 1)
+	murderPlan.AddRequiredRole(KeyPerson, 1)
+	murderPlan.AddRequiredRole(Brain, 1)
+	murderPlan.AddRequiredRole(Killer, 1)
 	MurderPlan = murderPlan
 
 	// Light of the Avenger
 	// Source: First Steps Main Plots in your knowledge base
 	lightOfTheAvenger := models.NewPlot("light_of_the_avenger", "Light of the Avenger", models.MainPlot, "At loop end, if there are at least 2 Intrigue counters on the Brain's starting location, the Protagonists lose.")
-	lightOfTheAvenger.AddRequiredRole("Brain", 1)
+	lightOfTheAvenger.AddRequiredRole(Brain, 1)
 	lightOfTheAvenger.AddRule(&LightOfTheAvengerFailureRule{})
 	LightOfTheAvenger = lightOfTheAvenger
 
 	// A Place to Protect
 	// Source: First Steps Main Plots in your knowledge base
 	aPlaceToProtect := models.NewPlot("a_place_to_protect", "A Place to Protect", models.MainPlot, "At loop end, if there are at least 2 Intrigue counters on the School, the Protagonists lose.")
-	aPlaceToProtect.AddRequiredRole("KeyPerson", 1)
-	aPlaceToProtect.AddRequiredRole("Cultist", 1)
+	aPlaceToProtect.AddRequiredRole(KeyPerson, 1)
+	aPlaceToProtect.AddRequiredRole(Cultist, 1)
 	aPlaceToProtect.AddRule(&APlaceToProtectFailureRule{})
 	APlaceToProtect = aPlaceToProtect
 
 	// Shadow of the Ripper
 	// Source: First Steps Subplots in your knowledge base
 	shadowOfTheRipper := models.NewPlot("shadow_of_the_ripper", "Shadow of the Ripper", models.SubPlot, "Roles to add: Conspiracy Theorist, Serial Killer.")
-	shadowOfTheRipper.AddRequiredRole("ConspiracyTheorist", 1)
-	shadowOfTheRipper.AddRequiredRole("SerialKiller", 1)
+	shadowOfTheRipper.AddRequiredRole(ConspiracyTheorist, 1)
+	shadowOfTheRipper.AddRequiredRole(SerialKiller, 1)
 	ShadowOfTheRipper = shadowOfTheRipper
 
 	// An Unsettling Rumor
 	// Source: First Steps Subplots in your knowledge base
 	anUnsettlingRumor := models.NewPlot("an_unsettling_rumor", "An Unsettling Rumor", models.SubPlot, "Once per loop, the Mastermind may add an Intrigue counter to any location.")
-	anUnsettlingRumor.AddRequiredRole("ConspiracyTheorist", 1)
+	anUnsettlingRumor.AddRequiredRole(ConspiracyTheorist, 1)
 	anUnsettlingRumor.AddRule(&AnUnsettlingRumorOptionalRule{})
 	AnUnsettlingRumor = anUnsettlingRumor
 
 	// A Hideous script
 	// Source: First Steps Subplots in your knowledge base
 	aHideousScript := models.NewPlot("a_hideous_script", "A Hideous script", models.SubPlot, "Roles to add: Conspiracy Theorist, Friend, 0-2 Curmudgeons.")
-	aHideousScript.AddRequiredRole("ConspiracyTheorist", 1)
-	aHideousScript.AddRequiredRole("Friend", 1)
-	//aHideousScript.AddOptionalRole("Curmudgeon", 0, 2) // Allows adding 0 to 2 Curmudgeons
+	aHideousScript.AddRequiredRole(ConspiracyTheorist, 1)
+	aHideousScript.AddRequiredRole(Friend, 1)
+	aHideousScript.AddOptionalRole(Curmudgeon, 0, 2)
 	AHideousScript = aHideousScript
 
+	for _, plot := range []*models.Plot{MurderPlan, LightOfTheAvenger, APlaceToProtect, ShadowOfTheRipper, AnUnsettlingRumor, AHideousScript} {
+		mustRegister(Set.AddPlot(plot))
+	}
 }
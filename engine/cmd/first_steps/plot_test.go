@@ -0,0 +1,115 @@
+package first_steps
+
+import (
+	"testing"
+	"tragedy-looper/engine/internal/models"
+)
+
+func TestEvaluateRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		mainPlot *models.Plot
+		subPlots []*models.Plot
+		roles    map[models.CharacterName]models.RoleType
+		// intrigue 判定前各位置的密谋
+		intrigue map[models.LocationType]int
+		timing   models.RuleTiming
+		// want 失败的剧情，按判定顺序
+		want []*models.Plot
+	}{
+		{
+			name:     "avenger with intrigue on the Brain's start",
+			mainPlot: LightOfTheAvenger,
+			roles:    map[models.CharacterName]models.RoleType{"OfficeWorker": Brain},
+			intrigue: map[models.LocationType]int{models.LocationCity: 2},
+			timing:   models.RuleTimingLoopEnd,
+			want:     []*models.Plot{LightOfTheAvenger},
+		},
+		{
+			name:     "avenger below the threshold",
+			mainPlot: LightOfTheAvenger,
+			roles:    map[models.CharacterName]models.RoleType{"OfficeWorker": Brain},
+			intrigue: map[models.LocationType]int{models.LocationCity: 1, models.LocationSchool: 3},
+			timing:   models.RuleTimingLoopEnd,
+		},
+		{
+			name:     "avenger uses the start location after the Brain moved",
+			mainPlot: LightOfTheAvenger,
+			roles:    map[models.CharacterName]models.RoleType{"BoyStudent": Brain},
+			intrigue: map[models.LocationType]int{models.LocationSchool: 2},
+			timing:   models.RuleTimingLoopEnd,
+			want:     []*models.Plot{LightOfTheAvenger},
+		},
+		{
+			name:     "avenger without a Brain",
+			mainPlot: LightOfTheAvenger,
+			intrigue: map[models.LocationType]int{models.LocationCity: 2},
+			timing:   models.RuleTimingLoopEnd,
+		},
+		{
+			name:     "place to protect with intrigue on the School",
+			mainPlot: APlaceToProtect,
+			intrigue: map[models.LocationType]int{models.LocationSchool: 2},
+			timing:   models.RuleTimingLoopEnd,
+			want:     []*models.Plot{APlaceToProtect},
+		},
+		{
+			name:     "rules only fire at their timing",
+			mainPlot: APlaceToProtect,
+			intrigue: map[models.LocationType]int{models.LocationSchool: 2},
+			timing:   models.RuleTimingDayEnd,
+		},
+		{
+			name:     "optional rules never lose the loop",
+			mainPlot: MurderPlan,
+			subPlots: []*models.Plot{AnUnsettlingRumor},
+			intrigue: map[models.LocationType]int{models.LocationSchool: 2},
+			timing:   models.RuleTimingMastermindAbilities,
+		},
+		{
+			name:     "main plot and subplot rules are both checked",
+			mainPlot: LightOfTheAvenger,
+			subPlots: []*models.Plot{ShadowOfTheRipper},
+			roles:    map[models.CharacterName]models.RoleType{"BoyStudent": Brain},
+			intrigue: map[models.LocationType]int{models.LocationSchool: 2},
+			timing:   models.RuleTimingLoopEnd,
+			want:     []*models.Plot{LightOfTheAvenger},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRoleFixture(t, tt.roles)
+			f.gs.Script = &models.Script{MainPlot: tt.mainPlot, SubPlots: tt.subPlots}
+			f.gs.CurrentLoop, f.gs.CurrentDay = 2, 3
+			for locationType, intrigue := range tt.intrigue {
+				f.target(string(locationType)).(*models.Location).SetIntrigue(intrigue)
+			}
+			f.move("BoyStudent", models.LocationShrine)
+
+			losses := f.gs.EvaluateRules(tt.timing)
+			if len(losses) != len(tt.want) {
+				t.Fatalf("got %d losses, want %d", len(losses), len(tt.want))
+			}
+			for i, loss := range losses {
+				if loss.Plot != tt.want[i] || loss.Rule == nil || loss.Reason != loss.Rule.GetDescription() {
+					t.Errorf("loss %d = %+v, want plot %s", i, loss, tt.want[i].ID)
+				}
+				if loss.Loop != 2 || loss.Day != 3 {
+					t.Errorf("loss %d recorded at loop %d day %d, want loop 2 day 3", i, loss.Loop, loss.Day)
+				}
+			}
+			if lost := f.gs.ProtagonistsLostLoop(); lost != (len(tt.want) > 0) {
+				t.Errorf("ProtagonistsLostLoop() = %v", lost)
+			}
+		})
+	}
+}
+
+func TestEvaluateRulesWithoutScript(t *testing.T) {
+	f := newRoleFixture(t, nil)
+	f.target(string(models.LocationSchool)).(*models.Location).SetIntrigue(3)
+	if losses := f.gs.EvaluateRules(models.RuleTimingLoopEnd); len(losses) != 0 || f.gs.ProtagonistsLostLoop() {
+		t.Errorf("losses = %v without a script", losses)
+	}
+}
@@ -1,6 +1,7 @@
 package first_steps
 
 import (
+	"fmt"
 	"tragedy-looper/engine/internal/models"
 )
 
@@ -32,9 +33,19 @@ func (roleAbility *KeyPersonRoleAbility) RoleType() models.RoleType {
 }
 
 func (roleAbility *KeyPersonRoleAbility) IsTriggerable(gameState *models.GameState, target models.RoleAbilityTarget) (bool, error) {
-	return true, nil
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return false, err
+	}
+	return !character.IsAlive(), nil
 }
 func (roleAbility *KeyPersonRoleAbility) Execute(gameState *models.GameState, target models.RoleAbilityTarget) error {
+	// The Key Person's death ends the loop immediately
+	source := string(KeyPerson)
+	if character, ok := target.(*models.Character); ok {
+		source = string(character.Name)
+	}
+	gameState.EndLoopEarly(models.LoopEndKeyPersonDead, source)
 	return nil
 }
 
@@ -42,11 +53,11 @@ func (roleAbility *KeyPersonRoleAbility) GetTiming() models.RoleAbilityTiming {
 	return models.RoleTimingCharacterDeath
 }
 
-func (roleAbility *KeyPersonRoleAbility) GetMandatory() models.GoodwillRefusal {
-	return models.GoodwillRefusalMust
+func (roleAbility *KeyPersonRoleAbility) GetMandatory() models.AbilityObligation {
+	return models.AbilityMandatory
 }
 
-// KillerAbility represents the Killer's ability to kill the Protagonists
+// KillerAbility represents the Killer's ability to kill the Key Person
 type KillerAbility struct{}
 
 func (roleAbility *KillerAbility) RoleType() models.RoleType {
@@ -54,20 +65,72 @@ func (roleAbility *KillerAbility) RoleType() models.RoleType {
 }
 
 func (roleAbility *KillerAbility) IsTriggerable(gameState *models.GameState, target models.RoleAbilityTarget) (bool, error) {
-	return true, nil
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return false, err
+	}
+	return len(roleAbility.TargetOptions(gameState, character)) > 0, nil
 }
 
 func (roleAbility *KillerAbility) Execute(gameState *models.GameState, target models.RoleAbilityTarget) error {
-	// Kill the Protagonists
-	return nil
+	// Kill the Key Person
+	victim, ok := target.(*models.Character)
+	if !ok || !victim.HasRole(KeyPerson) {
+		return fmt.Errorf("killer target must be the Key Person")
+	}
+	_, err := gameState.KillCharacter(victim, models.DeathByAbility, roleHolderName(gameState, Killer))
+	return err
+}
+
+// TargetOptions returns the Key Person if it is in the same location with at least 2 Intrigue
+func (roleAbility *KillerAbility) TargetOptions(gameState *models.GameState, source *models.Character) []models.TargetType {
+	options := make([]models.TargetType, 0, 1)
+	for _, character := range charactersAt(gameState, source.Location(), source) {
+		if character.HasRole(KeyPerson) && character.Intrigue() >= 2 {
+			options = append(options, character)
+		}
+	}
+	return options
 }
 
 func (roleAbility *KillerAbility) GetTiming() models.RoleAbilityTiming {
 	return models.RoleTimingDayEnd
 }
 
-func (roleAbility *KillerAbility) GetMandatory() models.GoodwillRefusal {
-	return models.GoodwillRefusalOptional
+func (roleAbility *KillerAbility) GetMandatory() models.AbilityObligation {
+	return models.AbilityOptional
+}
+
+// KillerProtagonistsAbility represents the Killer's ability to kill the Protagonists
+type KillerProtagonistsAbility struct{}
+
+func (roleAbility *KillerProtagonistsAbility) RoleType() models.RoleType {
+	return Killer
+}
+
+func (roleAbility *KillerProtagonistsAbility) IsTriggerable(gameState *models.GameState, target models.RoleAbilityTarget) (bool, error) {
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return false, err
+	}
+	return character.Intrigue() >= 4, nil
+}
+
+func (roleAbility *KillerProtagonistsAbility) Execute(gameState *models.GameState, target models.RoleAbilityTarget) error {
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return err
+	}
+	gameState.KillProtagonists(string(character.Name))
+	return nil
+}
+
+func (roleAbility *KillerProtagonistsAbility) GetTiming() models.RoleAbilityTiming {
+	return models.RoleTimingDayEnd
+}
+
+func (roleAbility *KillerProtagonistsAbility) GetMandatory() models.AbilityObligation {
+	return models.AbilityOptional
 }
 
 // BrainAbility represents the Brain's ability to add Intrigue
@@ -78,19 +141,79 @@ func (roleAbility *BrainAbility) RoleType() models.RoleType {
 }
 
 func (roleAbility *BrainAbility) IsTriggerable(gameState *models.GameState, target models.RoleAbilityTarget) (bool, error) {
-	return true, nil
+	_, err := sourceCharacter(target)
+	return err == nil, err
 }
 
 func (roleAbility *BrainAbility) Execute(gameState *models.GameState, target models.RoleAbilityTarget) error {
+	// Add 1 Intrigue to the Brain's location or a character there
+	targetType, ok := target.(models.TargetType)
+	if !ok {
+		return fmt.Errorf("brain target must be a character or location")
+	}
+	targetType.SetIntrigue(targetType.Intrigue() + 1)
 	return nil
 }
 
+// TargetOptions returns the Brain's location and the characters in it
+func (roleAbility *BrainAbility) TargetOptions(gameState *models.GameState, source *models.Character) []models.TargetType {
+	options := make([]models.TargetType, 0)
+	if location := gameState.Location(source.Location()); location != nil {
+		options = append(options, location)
+	}
+	for _, character := range charactersAt(gameState, source.Location(), nil) {
+		options = append(options, character)
+	}
+	return options
+}
+
 func (roleAbility *BrainAbility) GetTiming() models.RoleAbilityTiming {
 	return models.RoleTimingMastermind
 }
 
-func (roleAbility *BrainAbility) GetMandatory() models.GoodwillRefusal {
-	return models.GoodwillRefusalOptional
+func (roleAbility *BrainAbility) GetMandatory() models.AbilityObligation {
+	return models.AbilityOptional
+}
+
+// CultistAbility represents the Cultist's ability to ignore Forbid Intrigue on itself or its location
+type CultistAbility struct{}
+
+func (roleAbility *CultistAbility) RoleType() models.RoleType {
+	return Cultist
+}
+
+func (roleAbility *CultistAbility) IsTriggerable(gameState *models.GameState, target models.RoleAbilityTarget) (bool, error) {
+	return false, nil
+}
+
+func (roleAbility *CultistAbility) Execute(gameState *models.GameState, target models.RoleAbilityTarget) error {
+	return nil
+}
+
+func (roleAbility *CultistAbility) GetTiming() models.RoleAbilityTiming {
+	return models.RoleTimingCardResolve
+}
+
+func (roleAbility *CultistAbility) GetMandatory() models.AbilityObligation {
+	return models.AbilityOptional
+}
+
+// IgnoresForbid ignores the Protagonists' Forbid Intrigue placed on the Cultist or its location
+func (roleAbility *CultistAbility) IgnoresForbid(gameState *models.GameState, source *models.Character, forbid models.Card) bool {
+	if forbid.Type() != models.ForbidIntrigueType {
+		return false
+	}
+	if models.SeatOf(forbid.Owner()) != models.SeatProtagonist {
+		return false
+	}
+	switch target := forbid.Target().(type) {
+	case *models.Character:
+		return target == source
+	case *models.Location:
+		return target.LocationType == source.Location()
+	default:
+		return false
+	}
 }
 
 // FriendDeathCheckAbility represents the Friend's ability causing Protagonists to lose if dead at loop end
@@ -101,10 +224,21 @@ func (roleAbility *FriendDeathCheckAbility) RoleType() models.RoleType {
 }
 
 func (roleAbility *FriendDeathCheckAbility) IsTriggerable(gameState *models.GameState, target models.RoleAbilityTarget) (bool, error) {
-	return true, nil
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return false, err
+	}
+	return !character.IsAlive(), nil
 }
 
 func (roleAbility *FriendDeathCheckAbility) Execute(gameState *models.GameState, target models.RoleAbilityTarget) error {
+	// The Protagonists lose if the Friend is dead at loop end, and its role is revealed
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return err
+	}
+	gameState.RevealRole(character.Name)
+	gameState.LoseLoop(&models.LoopLoss{Reason: fmt.Sprintf("Friend %s is dead at loop end", character.Name)})
 	return nil
 }
 
@@ -112,8 +246,8 @@ func (roleAbility *FriendDeathCheckAbility) GetTiming() models.RoleAbilityTiming
 	return models.RoleTimingLoopEnd
 }
 
-func (roleAbility *FriendDeathCheckAbility) GetMandatory() models.GoodwillRefusal {
-	return models.GoodwillRefusalMust
+func (roleAbility *FriendDeathCheckAbility) GetMandatory() models.AbilityObligation {
+	return models.AbilityMandatory
 }
 
 // FriendGoodwillAbility represents the Friend's ability to gain Goodwill if role is revealed at loop start
@@ -124,10 +258,20 @@ func (roleAbility *FriendGoodwillAbility) RoleType() models.RoleType {
 }
 
 func (roleAbility *FriendGoodwillAbility) IsTriggerable(gameState *models.GameState, target models.RoleAbilityTarget) (bool, error) {
-	return true, nil
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return false, err
+	}
+	return character.IsAlive() && gameState.RoleRevealed(character.Name), nil
 }
 
 func (roleAbility *FriendGoodwillAbility) Execute(gameState *models.GameState, target models.RoleAbilityTarget) error {
+	// Place 1 Goodwill on the Friend once its role has been revealed
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return err
+	}
+	character.SetGoodwill(character.Goodwill() + 1)
 	return nil
 }
 
@@ -135,8 +279,8 @@ func (roleAbility *FriendGoodwillAbility) GetTiming() models.RoleAbilityTiming {
 	return models.RoleTimingLoopStart
 }
 
-func (roleAbility *FriendGoodwillAbility) GetMandatory() models.GoodwillRefusal {
-	return models.GoodwillRefusalMust
+func (roleAbility *FriendGoodwillAbility) GetMandatory() models.AbilityObligation {
+	return models.AbilityMandatory
 }
 
 // ConspiracyTheoristAbility represents the Conspiracy Theorist's ability to add Paranoia
@@ -147,19 +291,43 @@ func (roleAbility *ConspiracyTheoristAbility) RoleType() models.RoleType {
 }
 
 func (roleAbility *ConspiracyTheoristAbility) IsTriggerable(gameState *models.GameState, target models.RoleAbilityTarget) (bool, error) {
-	return true, nil
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return false, err
+	}
+	return len(roleAbility.TargetOptions(gameState, character)) > 0, nil
 }
 
 func (roleAbility *ConspiracyTheoristAbility) Execute(gameState *models.GameState, target models.RoleAbilityTarget) error {
+	// Add 1 Paranoia to a character in the same location
+	character, ok := target.(*models.Character)
+	if !ok {
+		return fmt.Errorf("conspiracy theorist target must be a character")
+	}
+	character.SetParanoia(character.Paranoia() + 1)
 	return nil
 }
 
+// TargetOptions returns the characters in the Conspiracy Theorist's location
+func (roleAbility *ConspiracyTheoristAbility) TargetOptions(gameState *models.GameState, source *models.Character) []models.TargetType {
+	options := make([]models.TargetType, 0)
+	for _, character := range charactersAt(gameState, source.Location(), nil) {
+		options = append(options, character)
+	}
+	return options
+}
+
+// OncePerLoop the Conspiracy Theorist can add Paranoia once per loop
+func (roleAbility *ConspiracyTheoristAbility) OncePerLoop() bool {
+	return true
+}
+
 func (roleAbility *ConspiracyTheoristAbility) GetTiming() models.RoleAbilityTiming {
 	return models.RoleTimingMastermind
 }
 
-func (roleAbility *ConspiracyTheoristAbility) GetMandatory() models.GoodwillRefusal {
-	return models.GoodwillRefusalOptional
+func (roleAbility *ConspiracyTheoristAbility) GetMandatory() models.AbilityObligation {
+	return models.AbilityOptional
 }
 
 // SerialKillerAbility represents the Serial Killer's ability to kill when alone with another character
@@ -170,19 +338,38 @@ func (roleAbility *SerialKillerAbility) RoleType() models.RoleType {
 }
 
 func (roleAbility *SerialKillerAbility) IsTriggerable(gameState *models.GameState, target models.RoleAbilityTarget) (bool, error) {
-	return true, nil
+	character, err := sourceCharacter(target)
+	if err != nil {
+		return false, err
+	}
+	return len(roleAbility.TargetOptions(gameState, character)) == 1, nil
 }
 
 func (roleAbility *SerialKillerAbility) Execute(gameState *models.GameState, target models.RoleAbilityTarget) error {
-	return nil
+	// Kill the only other character in the same location
+	victim, ok := target.(*models.Character)
+	if !ok {
+		return fmt.Errorf("serial killer target must be a character")
+	}
+	_, err := gameState.KillCharacter(victim, models.DeathByAbility, roleHolderName(gameState, SerialKiller))
+	return err
+}
+
+// TargetOptions returns the other character if exactly one is in the same location
+func (roleAbility *SerialKillerAbility) TargetOptions(gameState *models.GameState, source *models.Character) []models.TargetType {
+	others := charactersAt(gameState, source.Location(), source)
+	if len(others) != 1 {
+		return nil
+	}
+	return []models.TargetType{others[0]}
 }
 
 func (roleAbility *SerialKillerAbility) GetTiming() models.RoleAbilityTiming {
 	return models.RoleTimingDayEnd
 }
 
-func (roleAbility *SerialKillerAbility) GetMandatory() models.GoodwillRefusal {
-	return models.GoodwillRefusalMust
+func (roleAbility *SerialKillerAbility) GetMandatory() models.AbilityObligation {
+	return models.AbilityMandatory
 }
 
 // CurmudgeonRole represents the Curmudgeon role with no special abilities
@@ -206,6 +393,36 @@ func (roleAbility *CurmudgeonRole) GetTiming() models.RoleAbilityTiming {
 	return models.RoleTimingAlways
 }
 
-func (roleAbility *CurmudgeonRole) GetMandatory() models.GoodwillRefusal {
-	return models.GoodwillRefusalMust
+func (roleAbility *CurmudgeonRole) GetMandatory() models.AbilityObligation {
+	return models.AbilityMandatory
+}
+
+// sourceCharacter returns the character that owns the triggered ability
+func sourceCharacter(target models.RoleAbilityTarget) (*models.Character, error) {
+	character, ok := target.(*models.Character)
+	if !ok {
+		return nil, fmt.Errorf("ability source must be a character")
+	}
+	return character, nil
+}
+
+// charactersAt returns the living characters in a location, except the excluded one
+func charactersAt(gameState *models.GameState, location models.LocationType, exclude *models.Character) []*models.Character {
+	characters := make([]*models.Character, 0)
+	for _, character := range gameState.Characters {
+		if character != exclude && character.IsAlive() && character.IsAtLocation(location) {
+			characters = append(characters, character)
+		}
+	}
+	return characters
+}
+
+// roleHolderName returns the name of the character holding the role, or the role itself if none
+func roleHolderName(gameState *models.GameState, roleType models.RoleType) string {
+	for _, character := range gameState.Characters {
+		if character.HasRole(roleType) {
+			return string(character.Name)
+		}
+	}
+	return string(roleType)
 }
@@ -0,0 +1,456 @@
+package first_steps
+
+import (
+	"testing"
+	"tragedy-looper/engine/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// roleFixture 测试身份能力用的最小游戏状态：BoyStudent、GirlStudent 在学校，ShrineMaiden 在神社，OfficeWorker 在都市
+type roleFixture struct {
+	t  *testing.T
+	gs *models.GameState
+}
+
+func newRoleFixture(t *testing.T, roles map[models.CharacterName]models.RoleType) *roleFixture {
+	t.Helper()
+	gs := models.NewGameState(zap.NewNop())
+	for _, name := range []models.CharacterName{"BoyStudent", "GirlStudent", "ShrineMaiden", "OfficeWorker"} {
+		roleType, ok := roles[name]
+		if !ok {
+			roleType = models.RolePersonType
+		}
+		character, ok := Set.NewCharacter(name, NewRole(roleType))
+		if !ok {
+			t.Fatalf("unknown character %s", name)
+		}
+		gs.Characters = append(gs.Characters, character)
+	}
+	gs.Board = models.NewBoard(zap.NewNop(), gs.Characters)
+	if err := gs.Board.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	gs.CurrentLoop, gs.CurrentDay = 1, 1
+	return &roleFixture{t: t, gs: gs}
+}
+
+func (f *roleFixture) character(name models.CharacterName) *models.Character {
+	f.t.Helper()
+	character := f.gs.Character(name)
+	if character == nil {
+		f.t.Fatalf("unknown character %s", name)
+	}
+	return character
+}
+
+// target 按名称查找角色或位置
+func (f *roleFixture) target(name string) models.TargetType {
+	f.t.Helper()
+	if character := f.gs.Character(models.CharacterName(name)); character != nil {
+		return character
+	}
+	if location := f.gs.Location(models.LocationType(name)); location != nil {
+		return location
+	}
+	f.t.Fatalf("unknown target %s", name)
+	return nil
+}
+
+func (f *roleFixture) kill(name models.CharacterName) {
+	f.t.Helper()
+	if _, err := f.gs.KillCharacter(f.character(name), models.DeathByEffect, "test"); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+func (f *roleFixture) move(name models.CharacterName, location models.LocationType) {
+	f.t.Helper()
+	if err := f.gs.Board.MoveTo(f.character(name), location); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+func TestRoleAbilities(t *testing.T) {
+	school := string(models.LocationSchool)
+
+	tests := []struct {
+		name       string
+		roles      map[models.CharacterName]models.RoleType
+		source     models.CharacterName
+		ability    models.RoleAbility
+		timing     models.RoleAbilityTiming
+		obligation models.AbilityObligation
+		setup      func(f *roleFixture)
+		// triggerable 是否满足发动条件，满足时才检查目标和效果
+		triggerable bool
+		// targets 可选目标，nil 表示能力不需要选择目标
+		targets []string
+		// target 发动时的目标，为空时为能力所属角色
+		target string
+		check  func(t *testing.T, f *roleFixture)
+	}{
+		{
+			name:        "key person alive",
+			roles:       map[models.CharacterName]models.RoleType{"GirlStudent": KeyPerson},
+			source:      "GirlStudent",
+			ability:     &KeyPersonRoleAbility{},
+			timing:      models.RoleTimingCharacterDeath,
+			obligation:  models.AbilityMandatory,
+			triggerable: false,
+		},
+		{
+			name:        "key person death ends the loop",
+			roles:       map[models.CharacterName]models.RoleType{"GirlStudent": KeyPerson},
+			source:      "GirlStudent",
+			ability:     &KeyPersonRoleAbility{},
+			timing:      models.RoleTimingCharacterDeath,
+			obligation:  models.AbilityMandatory,
+			setup:       func(f *roleFixture) { f.kill("GirlStudent") },
+			triggerable: true,
+			check: func(t *testing.T, f *roleFixture) {
+				if !f.gs.LoopEndedEarly() || f.gs.LoopEnd.Cause != models.LoopEndKeyPersonDead {
+					t.Errorf("loop end = %+v, want key person dead", f.gs.LoopEnd)
+				}
+			},
+		},
+		{
+			name:        "killer needs 2 intrigue on the key person",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": Killer, "GirlStudent": KeyPerson},
+			source:      "BoyStudent",
+			ability:     &KillerAbility{},
+			timing:      models.RoleTimingDayEnd,
+			obligation:  models.AbilityOptional,
+			setup:       func(f *roleFixture) { f.character("GirlStudent").SetIntrigue(1) },
+			triggerable: false,
+		},
+		{
+			name:        "killer kills the key person",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": Killer, "GirlStudent": KeyPerson},
+			source:      "BoyStudent",
+			ability:     &KillerAbility{},
+			timing:      models.RoleTimingDayEnd,
+			obligation:  models.AbilityOptional,
+			setup:       func(f *roleFixture) { f.character("GirlStudent").SetIntrigue(2) },
+			triggerable: true,
+			targets:     []string{"GirlStudent"},
+			target:      "GirlStudent",
+			check: func(t *testing.T, f *roleFixture) {
+				if f.character("GirlStudent").IsAlive() {
+					t.Error("key person should be dead")
+				}
+			},
+		},
+		{
+			name:        "killer ignores a key person elsewhere",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": Killer, "ShrineMaiden": KeyPerson},
+			source:      "BoyStudent",
+			ability:     &KillerAbility{},
+			timing:      models.RoleTimingDayEnd,
+			obligation:  models.AbilityOptional,
+			setup:       func(f *roleFixture) { f.character("ShrineMaiden").SetIntrigue(2) },
+			triggerable: false,
+		},
+		{
+			name:        "killer below 4 intrigue",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": Killer},
+			source:      "BoyStudent",
+			ability:     &KillerProtagonistsAbility{},
+			timing:      models.RoleTimingDayEnd,
+			obligation:  models.AbilityOptional,
+			setup:       func(f *roleFixture) { f.character("BoyStudent").SetIntrigue(3) },
+			triggerable: false,
+		},
+		{
+			name:        "killer with 4 intrigue kills the protagonists",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": Killer},
+			source:      "BoyStudent",
+			ability:     &KillerProtagonistsAbility{},
+			timing:      models.RoleTimingDayEnd,
+			obligation:  models.AbilityOptional,
+			setup:       func(f *roleFixture) { f.character("BoyStudent").SetIntrigue(4) },
+			triggerable: true,
+			check: func(t *testing.T, f *roleFixture) {
+				if !f.gs.LoopEndedEarly() || f.gs.LoopEnd.Cause != models.LoopEndProtagonistsDead {
+					t.Errorf("loop end = %+v, want protagonists dead", f.gs.LoopEnd)
+				}
+			},
+		},
+		{
+			name:        "brain adds intrigue to its location",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": Brain},
+			source:      "BoyStudent",
+			ability:     &BrainAbility{},
+			timing:      models.RoleTimingMastermind,
+			obligation:  models.AbilityOptional,
+			triggerable: true,
+			targets:     []string{school, "BoyStudent", "GirlStudent"},
+			target:      school,
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.gs.Location(models.LocationSchool).Intrigue(); got != 1 {
+					t.Errorf("school intrigue = %d, want 1", got)
+				}
+			},
+		},
+		{
+			name:        "brain adds intrigue to a character there",
+			roles:       map[models.CharacterName]models.RoleType{"ShrineMaiden": Brain},
+			source:      "ShrineMaiden",
+			ability:     &BrainAbility{},
+			timing:      models.RoleTimingMastermind,
+			obligation:  models.AbilityOptional,
+			triggerable: true,
+			targets:     []string{string(models.LocationShrine), "ShrineMaiden"},
+			target:      "ShrineMaiden",
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.character("ShrineMaiden").Intrigue(); got != 1 {
+					t.Errorf("intrigue = %d, want 1", got)
+				}
+			},
+		},
+		{
+			name:        "cultist has no triggered ability",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": Cultist},
+			source:      "BoyStudent",
+			ability:     &CultistAbility{},
+			timing:      models.RoleTimingCardResolve,
+			obligation:  models.AbilityOptional,
+			triggerable: false,
+		},
+		{
+			name:        "friend alive at loop end",
+			roles:       map[models.CharacterName]models.RoleType{"GirlStudent": Friend},
+			source:      "GirlStudent",
+			ability:     &FriendDeathCheckAbility{},
+			timing:      models.RoleTimingLoopEnd,
+			obligation:  models.AbilityMandatory,
+			triggerable: false,
+		},
+		{
+			name:        "dead friend loses the loop and is revealed",
+			roles:       map[models.CharacterName]models.RoleType{"GirlStudent": Friend},
+			source:      "GirlStudent",
+			ability:     &FriendDeathCheckAbility{},
+			timing:      models.RoleTimingLoopEnd,
+			obligation:  models.AbilityMandatory,
+			setup:       func(f *roleFixture) { f.kill("GirlStudent") },
+			triggerable: true,
+			check: func(t *testing.T, f *roleFixture) {
+				if !f.gs.ProtagonistsLostLoop() {
+					t.Error("protagonists should lose the loop")
+				}
+				if !f.gs.RoleRevealed("GirlStudent") {
+					t.Error("friend role should be revealed")
+				}
+			},
+		},
+		{
+			name:        "hidden friend gains no goodwill",
+			roles:       map[models.CharacterName]models.RoleType{"GirlStudent": Friend},
+			source:      "GirlStudent",
+			ability:     &FriendGoodwillAbility{},
+			timing:      models.RoleTimingLoopStart,
+			obligation:  models.AbilityMandatory,
+			triggerable: false,
+		},
+		{
+			name:        "revealed friend gains goodwill at loop start",
+			roles:       map[models.CharacterName]models.RoleType{"GirlStudent": Friend},
+			source:      "GirlStudent",
+			ability:     &FriendGoodwillAbility{},
+			timing:      models.RoleTimingLoopStart,
+			obligation:  models.AbilityMandatory,
+			setup:       func(f *roleFixture) { f.gs.RevealRole("GirlStudent") },
+			triggerable: true,
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.character("GirlStudent").Goodwill(); got != 1 {
+					t.Errorf("goodwill = %d, want 1", got)
+				}
+			},
+		},
+		{
+			name:        "conspiracy theorist adds paranoia once per loop",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": ConspiracyTheorist},
+			source:      "BoyStudent",
+			ability:     &ConspiracyTheoristAbility{},
+			timing:      models.RoleTimingMastermind,
+			obligation:  models.AbilityOptional,
+			triggerable: true,
+			targets:     []string{"BoyStudent", "GirlStudent"},
+			target:      "GirlStudent",
+			check: func(t *testing.T, f *roleFixture) {
+				if got := f.character("GirlStudent").Paranoia(); got != 1 {
+					t.Errorf("paranoia = %d, want 1", got)
+				}
+			},
+		},
+		{
+			name:        "serial killer with nobody around",
+			roles:       map[models.CharacterName]models.RoleType{"ShrineMaiden": SerialKiller},
+			source:      "ShrineMaiden",
+			ability:     &SerialKillerAbility{},
+			timing:      models.RoleTimingDayEnd,
+			obligation:  models.AbilityMandatory,
+			triggerable: false,
+		},
+		{
+			name:        "serial killer with two others",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": SerialKiller},
+			source:      "BoyStudent",
+			ability:     &SerialKillerAbility{},
+			timing:      models.RoleTimingDayEnd,
+			obligation:  models.AbilityMandatory,
+			setup:       func(f *roleFixture) { f.move("ShrineMaiden", models.LocationSchool) },
+			triggerable: false,
+		},
+		{
+			name:        "serial killer alone with one character kills it",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": SerialKiller},
+			source:      "BoyStudent",
+			ability:     &SerialKillerAbility{},
+			timing:      models.RoleTimingDayEnd,
+			obligation:  models.AbilityMandatory,
+			triggerable: true,
+			targets:     []string{"GirlStudent"},
+			target:      "GirlStudent",
+			check: func(t *testing.T, f *roleFixture) {
+				if f.character("GirlStudent").IsAlive() {
+					t.Error("victim should be dead")
+				}
+			},
+		},
+		{
+			name:        "curmudgeon has no ability",
+			roles:       map[models.CharacterName]models.RoleType{"BoyStudent": Curmudgeon},
+			source:      "BoyStudent",
+			ability:     &CurmudgeonRole{},
+			timing:      models.RoleTimingAlways,
+			obligation:  models.AbilityMandatory,
+			triggerable: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRoleFixture(t, tt.roles)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			source := f.character(tt.source)
+
+			if got := tt.ability.GetTiming(); got != tt.timing {
+				t.Errorf("timing = %s, want %s", got, tt.timing)
+			}
+			if got := tt.ability.GetMandatory(); got != tt.obligation {
+				t.Errorf("obligation = %s, want %s", got, tt.obligation)
+			}
+			if got := tt.ability.RoleType(); got != tt.roles[tt.source] {
+				t.Errorf("role type = %s, want %s", got, tt.roles[tt.source])
+			}
+
+			triggerable, err := tt.ability.IsTriggerable(f.gs, source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if triggerable != tt.triggerable {
+				t.Fatalf("triggerable = %v, want %v", triggerable, tt.triggerable)
+			}
+			if !triggerable {
+				return
+			}
+
+			if targeted, ok := tt.ability.(models.TargetedRoleAbility); ok {
+				var names []string
+				for _, option := range targeted.TargetOptions(f.gs, source) {
+					names = append(names, models.TargetName(option))
+				}
+				if !sameNames(names, tt.targets) {
+					t.Errorf("targets = %v, want %v", names, tt.targets)
+				}
+			} else if tt.targets != nil {
+				t.Errorf("ability has no target options, want %v", tt.targets)
+			}
+
+			var target models.RoleAbilityTarget = source
+			if tt.target != "" {
+				target = f.target(tt.target)
+			}
+			if err := tt.ability.Execute(f.gs, target); err != nil {
+				t.Fatal(err)
+			}
+			if tt.check != nil {
+				tt.check(t, f)
+			}
+		})
+	}
+}
+
+func TestConspiracyTheoristOncePerLoop(t *testing.T) {
+	f := newRoleFixture(t, map[models.CharacterName]models.RoleType{"BoyStudent": ConspiracyTheorist})
+	source := f.character("BoyStudent")
+	ability := source.Role().Abilities[0]
+
+	if !f.gs.CanUseRoleAbility(source, ability) {
+		t.Fatal("ability should be usable before it is used")
+	}
+	if err := ability.Execute(f.gs, f.character("GirlStudent")); err != nil {
+		t.Fatal(err)
+	}
+	f.gs.MarkRoleAbilityUsed(source, ability)
+	if f.gs.CanUseRoleAbility(source, ability) {
+		t.Error("ability should not trigger twice in the same loop")
+	}
+	// 使用记录属于角色而不是能力实例
+	if !f.gs.CanUseRoleAbility(f.character("GirlStudent"), ability) {
+		t.Error("usage should be tracked per character")
+	}
+	f.gs.UsedRoleAbilities = nil
+	if !f.gs.CanUseRoleAbility(source, ability) {
+		t.Error("ability should trigger again in the next loop")
+	}
+}
+
+func TestRoleAbilityTargetErrors(t *testing.T) {
+	f := newRoleFixture(t, map[models.CharacterName]models.RoleType{
+		"BoyStudent":  Killer,
+		"GirlStudent": KeyPerson,
+	})
+	tests := []struct {
+		name    string
+		ability models.RoleAbility
+		target  models.RoleAbilityTarget
+	}{
+		{"killer on a person", &KillerAbility{}, f.character("ShrineMaiden")},
+		{"killer on a location", &KillerAbility{}, f.target(string(models.LocationSchool))},
+		{"conspiracy theorist on a location", &ConspiracyTheoristAbility{}, f.target(string(models.LocationSchool))},
+		{"serial killer on a location", &SerialKillerAbility{}, f.target(string(models.LocationSchool))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ability.Execute(f.gs, tt.target); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+	if _, err := (&KeyPersonRoleAbility{}).IsTriggerable(f.gs, f.target(string(models.LocationSchool))); err == nil {
+		t.Error("expected an error for a non-character source")
+	}
+}
+
+// sameNames 两组名称是否相同，忽略顺序
+func sameNames(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	counts := make(map[string]int)
+	for _, name := range got {
+		counts[name]++
+	}
+	for _, name := range want {
+		counts[name]--
+		if counts[name] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -16,12 +16,14 @@ func NewFirstSteps1() *models.Script {
 	// Character 医生	Brain 黑幕
 
 	firstSteps1 := &models.Script{
+		Title:      "First Steps 1",
+		TragedySet: SetID,
 		MainPlot:   MurderPlan,
 		SubPlots:   []*models.Plot{ShadowOfTheRipper},
 		Characters: make([]*models.Character, 0),
-		Incidents: []models.Incident{
-			&MurderIncident{},
-			&SuicideIncident{},
+		Incidents: []*models.ScheduledIncident{
+			{Incident: &MurderIncident{}, Day: 2, Culprit: "ShrineMaiden"},
+			{Incident: &SuicideIncident{}, Day: 3, Culprit: "BoyStudent"},
 		},
 		MaxLoops:    3,
 		DaysPerLoop: 3,
@@ -29,59 +31,12 @@ func NewFirstSteps1() *models.Script {
 
 	// 创建角色及其对应的身份
 	characters := []*models.Character{
-		// 男生 - Person(普通人)
-		NewBoyStudent(&models.Role{
-			Type: models.RolePersonType,
-			Name: "Person",
-			Abilities: []models.RoleAbility{
-				&models.RolePerson{},
-			},
-		}),
-
-		// 女学生 - Key Person(关键人物)
-		NewGirlStudent(&models.Role{
-			Type: KeyPerson,
-			Name: "Key Person",
-			Abilities: []models.RoleAbility{
-				&KeyPersonRoleAbility{},
-			},
-		}),
-
-		// 巫女 - Serial Killer(连环杀手)
-		NewShrineMaiden(&models.Role{
-			Type: SerialKiller,
-			Name: "Serial Killer",
-			Abilities: []models.RoleAbility{
-				&SerialKillerAbility{},
-			},
-		}),
-
-		// 警察 - Conspiracy Theorist(阴谋论者)
-		NewPoliceOfficer(&models.Role{
-			Type: ConspiracyTheorist,
-			Name: "Conspiracy Theorist",
-			Abilities: []models.RoleAbility{
-				&ConspiracyTheoristAbility{},
-			},
-		}),
-
-		// 上班族 - Killer(杀手)
-		NewOfficeWorker(&models.Role{
-			Type: Killer,
-			Name: "Killer",
-			Abilities: []models.RoleAbility{
-				&KillerAbility{},
-			},
-		}),
-
-		// 医生 - Brain(黑幕)
-		NewDoctor(&models.Role{
-			Type: Brain,
-			Name: "Brain",
-			Abilities: []models.RoleAbility{
-				&BrainAbility{},
-			},
-		}),
+		NewBoyStudent(NewRole(models.RolePersonType)), // 男生 - Person(普通人)
+		NewGirlStudent(NewRole(KeyPerson)),            // 女学生 - Key Person(关键人物)
+		NewShrineMaiden(NewRole(SerialKiller)),        // 巫女 - Serial Killer(连环杀手)
+		NewPoliceOfficer(NewRole(ConspiracyTheorist)), // 警察 - Conspiracy Theorist(阴谋论者)
+		NewOfficeWorker(NewRole(Killer)),              // 上班族 - Killer(杀手)
+		NewDoctor(NewRole(Brain)),                     // 医生 - Brain(黑幕)
 	}
 
 	firstSteps1.Characters = characters
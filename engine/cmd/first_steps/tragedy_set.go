@@ -0,0 +1,112 @@
+package first_steps
+
+import "tragedy-looper/engine/internal/models"
+
+// SetID First Steps 惨剧集的 ID
+const SetID = "first_steps"
+
+// Set First Steps 惨剧集，剧情在 plot.go 的 init 中注册
+var Set = models.NewTragedySet(SetID, "First Steps")
+
+func init() {
+	// 身份，每个剧本都创建新的实例
+	mustRegister(Set.AddRole(models.RolePersonType, func() *models.Role {
+		return &models.Role{Type: models.RolePersonType, Name: "Person", Abilities: []models.RoleAbility{&models.RolePerson{}}}
+	}))
+	mustRegister(Set.AddRole(KeyPerson, func() *models.Role {
+		return &models.Role{Type: KeyPerson, Name: "Key Person", Abilities: []models.RoleAbility{&KeyPersonRoleAbility{}}}
+	}))
+	mustRegister(Set.AddRole(Killer, func() *models.Role {
+		return &models.Role{
+			Type:            Killer,
+			Name:            "Killer",
+			Abilities:       []models.RoleAbility{&KillerAbility{}, &KillerProtagonistsAbility{}},
+			GoodwillRefusal: models.GoodwillRefusalOptional,
+		}
+	}))
+	mustRegister(Set.AddRole(Brain, func() *models.Role {
+		return &models.Role{
+			Type:            Brain,
+			Name:            "Brain",
+			Abilities:       []models.RoleAbility{&BrainAbility{}},
+			GoodwillRefusal: models.GoodwillRefusalOptional,
+		}
+	}))
+	mustRegister(Set.AddRole(Cultist, func() *models.Role {
+		return &models.Role{
+			Type:            Cultist,
+			Name:            "Cultist",
+			Abilities:       []models.RoleAbility{&CultistAbility{}},
+			GoodwillRefusal: models.GoodwillRefusalMandatory,
+		}
+	}))
+	mustRegister(Set.AddRole(Friend, func() *models.Role {
+		return &models.Role{Type: Friend, Name: "Friend", Abilities: []models.RoleAbility{&FriendDeathCheckAbility{}, &FriendGoodwillAbility{}}}
+	}))
+	mustRegister(Set.AddRole(ConspiracyTheorist, func() *models.Role {
+		return &models.Role{Type: ConspiracyTheorist, Name: "Conspiracy Theorist", Abilities: []models.RoleAbility{&ConspiracyTheoristAbility{}}}
+	}))
+	mustRegister(Set.AddRole(SerialKiller, func() *models.Role {
+		return &models.Role{Type: SerialKiller, Name: "Serial Killer", Abilities: []models.RoleAbility{&SerialKillerAbility{}}}
+	}))
+	mustRegister(Set.AddRole(Curmudgeon, func() *models.Role {
+		return &models.Role{
+			Type:            Curmudgeon,
+			Name:            "Curmudgeon",
+			Abilities:       []models.RoleAbility{&CurmudgeonRole{}},
+			GoodwillRefusal: models.GoodwillRefusalOptional,
+		}
+	}))
+
+	// 角色
+	for _, c := range []struct {
+		name    models.CharacterName
+		factory models.CharacterFactory
+	}{
+		{"BoyStudent", NewBoyStudent},
+		{"GirlStudent", NewGirlStudent},
+		{"RichMansDaughter", NewRichMansDaughter},
+		{"ClassRep", NewClassRep},
+		{"MysteryBoy", NewMysteryBoy},
+		{"ShrineMaiden", NewShrineMaiden},
+		{"Alien", NewAlien},
+		{"Godly", NewGodly},
+		{"PoliceOfficer", NewPoliceOfficer},
+		{"OfficeWorker", NewOfficeWorker},
+		{"Informer", NewInformer},
+		{"PopIdol", NewPopIdol},
+		{"Journalist", NewJournalist},
+		{"Boss", NewBoss},
+		{"Doctor", NewDoctor},
+		{"Patient", NewPatient},
+		{"Nurse", NewNurse},
+		{"Henchman", NewHenchman},
+		{"Outsider", NewOutsider},
+	} {
+		mustRegister(Set.AddCharacter(c.name, c.factory))
+	}
+
+	// 事件
+	mustRegister(Set.AddIncident(MurderIncidentType, func() models.Incident { return &MurderIncident{} }))
+	mustRegister(Set.AddIncident(FarawayMurderIncidentType, func() models.Incident { return &FarawayMurderIncident{} }))
+	mustRegister(Set.AddIncident(SuicideIncidentType, func() models.Incident { return &SuicideIncident{} }))
+	mustRegister(Set.AddIncident(HospitalIncidentType, func() models.Incident { return &HospitalIncident{} }))
+	mustRegister(Set.AddIncident(MissingIncidentType, func() models.Incident { return &MissingIncident{} }))
+	mustRegister(Set.AddIncident(IncreasingUneaseIncidentType, func() models.Incident { return &IncreasingUneaseIncident{} }))
+	mustRegister(Set.AddIncident(SpreadingIncidentType, func() models.Incident { return &SpreadingIncident{} }))
+
+	mustRegister(models.RegisterTragedySet(Set))
+}
+
+// NewRole 创建 First Steps 中的身份，未知身份时返回空
+func NewRole(roleType models.RoleType) *models.Role {
+	role, _ := Set.NewRole(roleType)
+	return role
+}
+
+// mustRegister 注册失败说明内容定义有误
+func mustRegister(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
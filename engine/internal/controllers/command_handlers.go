@@ -0,0 +1,544 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tragedy-looper/engine/internal/controllers/commands"
+	"tragedy-looper/engine/internal/models"
+)
+
+// registerDefaultHandlers 注册默认的命令处理函数
+//
+// move、triggerIncident、kill 等绕过引擎阶段流程直接修改游戏状态的命令不注册处理函数，
+// 执行时返回 ErrNotSupported；移动、事件和死亡只由引擎在对应阶段结算。
+func registerDefaultHandlers(d *CommandDispatcher) {
+	d.handlers[commands.CmdStartGame] = handleStartGame
+	d.handlers[commands.CmdPlaceCard] = handlePlaceCard
+	d.handlers[commands.CmdPassAction] = handlePass
+	d.handlers[commands.CmdNextPhase] = handleNextPhase
+	d.handlers[commands.CmdRefuseGoodwill] = handleRefuseGoodwill
+	d.handlers[commands.CmdSelectChar] = handleSelectTarget
+	d.handlers[commands.CmdSelectLocation] = handleSelectTarget
+	d.handlers[commands.CmdShowCards] = handleShowCards
+	d.handlers[commands.CmdShowBoard] = handleShowBoard
+	d.handlers[commands.CmdStatus] = handleStatus
+	d.handlers[commands.CmdViewRules] = handleViewRules
+	d.handlers[commands.CmdViewIncidents] = handleViewIncidents
+	d.handlers[commands.CmdUseGoodwill] = handleUseGoodwill
+	d.handlers[commands.CmdFinalGuess] = handleFinalGuess
+	d.handlers[commands.CmdHelp] = handleHelp
+	d.handlers[commands.CmdQuit] = handleQuit
+	d.handlers[commands.CmdCheckParanoia] = handleCheckParanoia
+	d.handlers[commands.CmdCheckIntrigue] = handleCheckIntrigue
+	d.handlers[commands.CmdUseMastermindAbility] = handleUseMastermindAbility
+	d.handlers[commands.CmdRevealRole] = handleRevealRole
+	d.handlers[commands.CmdCheckLossCondition] = handleCheckLossCondition
+	d.handlers[commands.CmdMakeNote] = handleMakeNote
+	d.handlers[commands.CmdSetupTimeSpiral] = handleTimeSpiral
+}
+
+func handleStartGame(ctx *CommandContext) (*CommandResult, error) {
+	if err := ctx.Game.Start(); err != nil {
+		return nil, err
+	}
+	return advance(ctx, "game started")
+}
+
+func handleNextPhase(ctx *CommandContext) (*CommandResult, error) {
+	if seat := ctx.Game.WaitingOn(); seat != "" {
+		return nil, fmt.Errorf("%w: waiting for %s", commands.ErrPhaseNotAllowed, seat)
+	}
+	return advance(ctx, fmt.Sprintf("%s/%s", ctx.Game.state.CurrentGamePhase, ctx.Game.state.CurrentDayPhase))
+}
+
+func handlePass(ctx *CommandContext) (*CommandResult, error) {
+	pending, err := pendingFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 放弃拒绝即允许领袖发动好感度能力
+	var answer any
+	if pending.Kind == DecisionGoodwillRefusal {
+		answer = false
+	}
+	return answerPending(ctx, answer, "passed")
+}
+
+func handleRefuseGoodwill(ctx *CommandContext) (*CommandResult, error) {
+	if _, err := pendingFor(ctx, DecisionGoodwillRefusal); err != nil {
+		return nil, err
+	}
+	return answerPending(ctx, true, "goodwill ability refused")
+}
+
+func handleSelectTarget(ctx *CommandContext) (*CommandResult, error) {
+	if _, err := pendingFor(ctx, DecisionAbilityTarget, DecisionIncidentTarget); err != nil {
+		return nil, err
+	}
+
+	state := ctx.Game.state
+	name := ctx.Command.Arg(0)
+	var target models.TargetType
+	if ctx.Command.Type == commands.CmdSelectChar {
+		if char := state.Character(models.CharacterName(name)); char != nil {
+			target = char
+		}
+	} else if loc := state.Board.GetLocation(models.LocationType(name)); loc != nil {
+		target = loc
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%w: %q", commands.ErrTargetNotFound, name)
+	}
+	return answerPending(ctx, target, fmt.Sprintf("selected %s", name))
+}
+
+func handlePlaceCard(ctx *CommandContext) (*CommandResult, error) {
+	pending, err := pendingFor(ctx, DecisionPlaceMastermindCards, DecisionPlaceProtagonistCard)
+	if err != nil {
+		return nil, err
+	}
+
+	state := ctx.Game.state
+	card := findHandCard(ctx.Player, ctx.Command.Arg(0))
+	if card == nil {
+		return nil, fmt.Errorf("%w: card %q", commands.ErrTargetNotFound, ctx.Command.Arg(0))
+	}
+	target := findTarget(state, ctx.Command.Arg(1))
+	if target == nil {
+		return nil, fmt.Errorf("%w: %q", commands.ErrTargetNotFound, ctx.Command.Arg(1))
+	}
+
+	if err = ctx.Game.placeCards(ctx.Player, []models.CardPlacement{{Card: card, Target: target}}); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("placed %s on %s", card.Id(), ctx.Command.Arg(1))
+	// 放满当天需要的卡牌后结束本次放置
+	if request := pending.Request.(*PlaceCardsRequest); ctx.Game.placedCards(ctx.Player) < request.Count {
+		return &CommandResult{Message: message, Data: card}, nil
+	}
+	return answerPending(ctx, nil, message)
+}
+
+func handleShowCards(ctx *CommandContext) (*CommandResult, error) {
+	ids := ctx.Player.GetHandCardIDs()
+	return &CommandResult{
+		Message: strings.Join(ids, ", "),
+		Data:    ids,
+	}, nil
+}
+
+// handleShowBoard 返回发出命令的席位可见的游戏状态
+func handleShowBoard(ctx *CommandContext) (*CommandResult, error) {
+	if ctx.Game.state.Board == nil {
+		return nil, fmt.Errorf("the board is not initialized")
+	}
+
+	view := ctx.Game.View(ctx.Seat)
+	lines := []string{fmt.Sprintf("Loop %d, Day %d", view.Loop, view.Day)}
+	for _, loc := range view.Locations {
+		lines = append(lines, fmt.Sprintf("%s (intrigue %d): %v", loc.Location, loc.Intrigue, loc.Characters))
+	}
+	for _, char := range view.Characters {
+		if char.Role != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", char.Name, char.Role))
+		}
+	}
+	for _, card := range view.Cards {
+		content := "face down"
+		if !card.Hidden {
+			content = string(card.Type)
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s on %s", card.Owner, content, card.Target))
+	}
+	return &CommandResult{Message: strings.Join(lines, "\n"), Data: view}, nil
+}
+
+// handleStatus 返回发出命令的席位可见的角色或位置状态
+func handleStatus(ctx *CommandContext) (*CommandResult, error) {
+	view := ctx.Game.View(ctx.Seat)
+	name := ctx.Command.Arg(0)
+	if char := view.Character(models.CharacterName(name)); char != nil {
+		message := fmt.Sprintf("%s @ %s alive=%v goodwill=%d/%d paranoia=%d/%d intrigue=%d",
+			char.Name, char.Location, char.IsAlive,
+			char.Goodwill, char.GoodwillLimit, char.Paranoia, char.ParanoiaLimit, char.Intrigue)
+		if char.Role != "" {
+			message += fmt.Sprintf(" role=%s", char.Role)
+		}
+		return &CommandResult{Message: message, Data: *char}, nil
+	}
+	if loc := view.Location(models.LocationType(name)); loc != nil {
+		return &CommandResult{
+			Message: fmt.Sprintf("%s intrigue=%d characters=%v", loc.Location, loc.Intrigue, loc.Characters),
+			Data:    *loc,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", commands.ErrTargetNotFound, name)
+}
+
+func handleViewRules(ctx *CommandContext) (*CommandResult, error) {
+	script := ctx.Game.script
+	lines := []string{fmt.Sprintf("Loops: %d, Days per loop: %d", script.MaxLoops, script.DaysPerLoop)}
+
+	// 剧情属于非公开信息，仅幕后主使可见
+	if ctx.Seat == models.SeatMastermind {
+		plots := append([]*models.Plot{script.MainPlot}, script.SubPlots...)
+		query := ctx.Command.Arg(0)
+		for _, plot := range plots {
+			if plot == nil || (query != "" && plot.Name != query) {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("[%s] %s: %s", plot.Type, plot.Name, plot.Description))
+			for _, rule := range plot.Rules {
+				lines = append(lines, fmt.Sprintf("  - (%s) %s", rule.GetRuleType(), rule.GetDescription()))
+			}
+		}
+		for _, char := range script.Characters {
+			if role := char.Role(); role != nil && (query == "" || role.Name == query) {
+				lines = append(lines, fmt.Sprintf("%s: %s", char.Name, role.Name))
+			}
+		}
+	}
+	return &CommandResult{Message: strings.Join(lines, "\n")}, nil
+}
+
+func handleViewIncidents(ctx *CommandContext) (*CommandResult, error) {
+	// 参数已由命令说明校验，为空时列出所有日期
+	day, _ := strconv.Atoi(ctx.Command.Arg(0))
+
+	view := ctx.Game.View(ctx.Seat)
+	occurred := make([]string, 0, len(view.Occurred))
+	for _, incident := range view.Occurred {
+		if day == 0 || incident.Day == day {
+			occurred = append(occurred, fmt.Sprintf("Loop %d Day %d: %s", incident.Loop, incident.Day, incident.Type))
+		}
+	}
+	return &CommandResult{Message: strings.Join(occurred, "\n"), Data: occurred}, nil
+}
+
+func handleUseGoodwill(ctx *CommandContext) (*CommandResult, error) {
+	pending, err := pendingFor(ctx, DecisionGoodwillAbility)
+	if err != nil {
+		return nil, err
+	}
+
+	name := models.CharacterName(ctx.Command.Arg(0))
+	var target models.TargetType
+	if targetName := ctx.Command.Arg(1); targetName != "" {
+		if target = findTarget(ctx.Game.state, targetName); target == nil {
+			return nil, fmt.Errorf("%w: %q", commands.ErrTargetNotFound, targetName)
+		}
+	}
+	for _, option := range pending.Request.(*GoodwillAbilityRequest).Options {
+		if option.Character != name || (target != nil && !containsRef(option.Targets, models.RefOf(target))) {
+			continue
+		}
+		option.Target = models.RefOf(target)
+		return answerPending(ctx, &option, fmt.Sprintf("%s used %s", name, option.Ability.Name))
+	}
+	return nil, fmt.Errorf("%w: no usable goodwill ability on %q", commands.ErrTargetNotFound, name)
+}
+
+func handleFinalGuess(ctx *CommandContext) (*CommandResult, error) {
+	if _, err := pendingFor(ctx, DecisionFinalGuess); err != nil {
+		return nil, err
+	}
+
+	state := ctx.Game.state
+	guess := make(map[models.CharacterName]models.RoleType)
+	for i := 0; i+1 < len(ctx.Command.Args); i += 2 {
+		char := state.Character(models.CharacterName(ctx.Command.Args[i]))
+		if char == nil {
+			return nil, fmt.Errorf("%w: %q", commands.ErrTargetNotFound, ctx.Command.Args[i])
+		}
+		guess[char.Name] = roleTypeByName(state, ctx.Command.Args[i+1])
+	}
+	result, err := answerPending(ctx, guess, "")
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := state.GuessResult
+	lines := []string{fmt.Sprintf("winner: %s (%d/%d correct)", state.WinnerType, outcome.Correct, len(outcome.Results))}
+	for _, r := range outcome.Results {
+		mark := "x"
+		if r.Correct {
+			mark = "o"
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s: guessed %s, was %s", mark, r.Character, r.Guessed, r.Actual))
+	}
+	result.Message = strings.Join(lines, "\n")
+	result.Data = outcome
+	return result, nil
+}
+
+func handleHelp(ctx *CommandContext) (*CommandResult, error) {
+	if name := ctx.Command.Arg(0); name != "" {
+		spec, ok := commands.Lookup(commands.CommandType(name))
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", commands.ErrUnknownCommand, name)
+		}
+		return &CommandResult{Message: fmt.Sprintf("%s - %s", spec.Syntax, spec.Summary), Data: spec}, nil
+	}
+
+	lines := make([]string, 0)
+	for _, spec := range commands.Specs() {
+		if spec.AllowsSeat(ctx.Seat) {
+			lines = append(lines, fmt.Sprintf("%-60s %s", spec.Syntax, spec.Summary))
+		}
+	}
+	return &CommandResult{Message: strings.Join(lines, "\n")}, nil
+}
+
+func handleQuit(ctx *CommandContext) (*CommandResult, error) {
+	return &CommandResult{Message: "bye"}, nil
+}
+
+func handleCheckParanoia(ctx *CommandContext) (*CommandResult, error) {
+	names := make([]string, 0)
+	for _, char := range ctx.Game.View(ctx.Seat).Characters {
+		if char.IsAlive && char.Paranoia >= char.ParanoiaLimit {
+			names = append(names, string(char.Name))
+		}
+	}
+	return &CommandResult{Message: strings.Join(names, ", "), Data: names}, nil
+}
+
+func handleCheckIntrigue(ctx *CommandContext) (*CommandResult, error) {
+	view := ctx.Game.View(ctx.Seat)
+	counters := make(map[string]int)
+	lines := make([]string, 0)
+	for _, loc := range view.Locations {
+		if loc.Intrigue > 0 {
+			counters[string(loc.Location)] = loc.Intrigue
+			lines = append(lines, fmt.Sprintf("%s: %d", loc.Location, loc.Intrigue))
+		}
+	}
+	for _, char := range view.Characters {
+		if char.Intrigue > 0 {
+			counters[string(char.Name)] = char.Intrigue
+			lines = append(lines, fmt.Sprintf("%s: %d", char.Name, char.Intrigue))
+		}
+	}
+	return &CommandResult{Message: strings.Join(lines, "\n"), Data: counters}, nil
+}
+
+// handleUseMastermindAbility 回答等待中的身份能力目标决策，能力只能在引擎询问时发动
+func handleUseMastermindAbility(ctx *CommandContext) (*CommandResult, error) {
+	pending, err := pendingFor(ctx, DecisionAbilityTarget)
+	if err != nil {
+		return nil, err
+	}
+	request := pending.Request.(*AbilityTargetRequest)
+	if request.Ability.GetTiming() != models.RoleTimingMastermind {
+		return nil, fmt.Errorf("%w: waiting for %s ability of %s",
+			commands.ErrPhaseNotAllowed, request.Ability.RoleType(), request.Character.Name)
+	}
+
+	name := models.CharacterName(ctx.Command.Arg(0))
+	if ctx.Game.state.Character(name) == nil {
+		return nil, fmt.Errorf("%w: %q", commands.ErrTargetNotFound, name)
+	}
+	if name != request.Character.Name {
+		return nil, fmt.Errorf("%w: waiting for %s ability of %s",
+			commands.ErrNotYourTurn, request.Ability.RoleType(), request.Character.Name)
+	}
+
+	var target models.TargetType
+	if arg := ctx.Command.Arg(1); arg != "" {
+		if target = findTarget(ctx.Game.state, arg); target == nil {
+			return nil, fmt.Errorf("%w: %q", commands.ErrTargetNotFound, arg)
+		}
+	} else if len(request.Options) == 1 {
+		target = request.Options[0]
+	} else {
+		return nil, fmt.Errorf("%w: %s ability needs a target", commands.ErrInvalidArguments, request.Ability.RoleType())
+	}
+	if !containsTarget(request.Options, target) {
+		return nil, fmt.Errorf("%w: %s for %s ability of %s",
+			commands.ErrInvalidTarget, models.TargetName(target), request.Ability.RoleType(), name)
+	}
+	return answerPending(ctx, target, fmt.Sprintf("%s used %s ability", name, request.Ability.RoleType()))
+}
+
+func handleRevealRole(ctx *CommandContext) (*CommandResult, error) {
+	char := ctx.Game.state.Character(models.CharacterName(ctx.Command.Arg(0)))
+	if char == nil {
+		return nil, fmt.Errorf("%w: %q", commands.ErrTargetNotFound, ctx.Command.Arg(0))
+	}
+	roleName := "Person"
+	if char.Role() != nil {
+		roleName = char.Role().Name
+	}
+	ctx.Game.state.RevealRole(char.Name)
+	return &CommandResult{Message: fmt.Sprintf("%s is %s", char.Name, roleName), Data: char.Role()}, nil
+}
+
+func handleCheckLossCondition(ctx *CommandContext) (*CommandResult, error) {
+	script := ctx.Game.script
+	name := ctx.Command.Arg(0)
+	for _, plot := range script.Plots() {
+		if plot.Name != name {
+			continue
+		}
+		met := len(plot.FailedRules(ctx.Game.state)) > 0
+		return &CommandResult{Message: fmt.Sprintf("%s loss condition met: %v", plot.Name, met), Data: met}, nil
+	}
+	return nil, fmt.Errorf("%w: plot %q", commands.ErrTargetNotFound, name)
+}
+
+func handleMakeNote(ctx *CommandContext) (*CommandResult, error) {
+	protagonist, ok := ctx.Player.(*models.Protagonist)
+	if !ok {
+		return nil, commands.ErrSeatNotAllowed
+	}
+	text := strings.Join(ctx.Command.Args, " ")
+	note, err := ctx.Game.state.TimeSpiral.AddNote(protagonist.ID, text, ctx.Game.now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", commands.ErrPhaseNotAllowed, err)
+	}
+	return &CommandResult{Message: fmt.Sprintf("note posted for loop %d", note.Loop), Data: note}, nil
+}
+
+func handleTimeSpiral(ctx *CommandContext) (*CommandResult, error) {
+	gc := ctx.Game
+	spiral := gc.state.TimeSpiral
+	if ctx.Command.Arg(0) != "ready" {
+		lines := make([]string, 0, len(spiral.Notes)+1)
+		if deadline := spiral.Deadline(); !deadline.IsZero() {
+			lines = append(lines, fmt.Sprintf("time left: %s", deadline.Sub(gc.now()).Round(time.Second)))
+		}
+		for _, note := range spiral.Notes {
+			lines = append(lines, fmt.Sprintf("[Loop %d] %s: %s", note.Loop, note.Author, note.Text))
+		}
+		return &CommandResult{Message: strings.Join(lines, "\n"), Data: spiral.Notes}, nil
+	}
+
+	// 任意主角都可以表示准备完毕，控制多副牌组的玩家同时代表其所有牌组
+	pending := gc.Pending(ctx.Seat)
+	if pending == nil || pending.Kind != DecisionTimeSpiral {
+		return nil, fmt.Errorf("%w: time spiral is not open", commands.ErrPhaseNotAllowed)
+	}
+	protagonist, ok := ctx.Player.(*models.Protagonist)
+	if !ok {
+		return nil, commands.ErrSeatNotAllowed
+	}
+	ready := make([]string, 0, len(gc.state.Protagonists))
+	for _, p := range gc.state.Protagonists {
+		if p.SameController(protagonist) {
+			ready = append(ready, p.ID)
+		}
+	}
+	return answerPending(ctx, ready, fmt.Sprintf("%s is ready", protagonist.ID))
+}
+
+// pendingFor 检查当前等待的决策属于调用者，kinds 为空时不限决策类型
+func pendingFor(ctx *CommandContext, kinds ...DecisionKind) (*PendingDecision, error) {
+	pending := ctx.Game.Pending(ctx.Seat)
+	if pending == nil {
+		if seat := ctx.Game.WaitingOn(); seat != "" {
+			return nil, fmt.Errorf("%w: waiting for %s", commands.ErrNotYourTurn, seat)
+		}
+		return nil, ErrNoPendingDecision
+	}
+	if len(kinds) > 0 {
+		matched := false
+		for _, kind := range kinds {
+			if pending.Kind == kind {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("%w: waiting for %s", commands.ErrPhaseNotAllowed, pending.Kind)
+		}
+	}
+	if pending.Player != ctx.Player && !sameController(pending.Player, ctx.Player) {
+		return nil, fmt.Errorf("%w: waiting for %s from %s", commands.ErrNotYourTurn, pending.Kind, pending.Seat)
+	}
+	// 玩家控制多副牌组时，代替当前需要决策的牌组行动
+	ctx.Player = pending.Player
+	return pending, nil
+}
+
+// sameController 两名主角是否由同一名玩家控制
+func sameController(a, b models.Player) bool {
+	pa, ok := a.(*models.Protagonist)
+	if !ok {
+		return false
+	}
+	pb, ok := b.(*models.Protagonist)
+	return ok && pa.SameController(pb)
+}
+
+// answerPending 回答当前决策并推进引擎
+func answerPending(ctx *CommandContext, answer any, message string) (*CommandResult, error) {
+	if err := ctx.Game.Answer(answer); err != nil {
+		return nil, err
+	}
+	return advance(ctx, message)
+}
+
+// advance 推进引擎直到下一个决策或游戏结束
+func advance(ctx *CommandContext, message string) (*CommandResult, error) {
+	if _, err := ctx.Game.Advance(); err != nil {
+		return nil, err
+	}
+	return &CommandResult{Message: message}, nil
+}
+
+// findHandCard 在玩家手牌中查找卡牌，支持 "goodwill+1" 这类写法
+func findHandCard(player models.Player, id string) models.Card {
+	normalized := normalizeCardID(id)
+	for _, card := range player.GetHandCards() {
+		if card.Id() == id || card.Id() == normalized {
+			return card
+		}
+	}
+	return nil
+}
+
+// normalizeCardID 将 "goodwill+1"、"paranoia-1" 转换为卡牌ID格式
+func normalizeCardID(id string) string {
+	id = strings.ToLower(id)
+	if strings.Contains(id, "_") {
+		return id
+	}
+	i := strings.IndexAny(id, "+-")
+	if i <= 0 {
+		return id
+	}
+	if _, err := strconv.Atoi(id[i:]); err != nil {
+		return id
+	}
+	value := strings.TrimPrefix(id[i:], "+")
+	return id[:i] + "_" + value
+}
+
+// roleTypeByName 按身份类型或身份名称查找身份类型
+func roleTypeByName(state *models.GameState, name string) models.RoleType {
+	for _, role := range state.Roles {
+		if role != nil && (role.Name == name || string(role.Type) == name) {
+			return role.Type
+		}
+	}
+	if name == "Person" {
+		return models.RolePersonType
+	}
+	return models.RoleType(name)
+}
+
+// findTarget 按名称查找角色或位置
+func findTarget(state *models.GameState, name string) models.TargetType {
+	if char := state.Character(models.CharacterName(name)); char != nil {
+		return char
+	}
+	if state.Board != nil {
+		if loc := state.Board.GetLocation(models.LocationType(name)); loc != nil {
+			return loc
+		}
+	}
+	return nil
+}
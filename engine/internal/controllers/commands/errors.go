@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEmptyCommand 输入为空
+	ErrEmptyCommand = errors.New("empty command")
+	// ErrUnterminatedQuote 引号未闭合
+	ErrUnterminatedQuote = errors.New("unterminated quote")
+	// ErrUnknownCommand 未知的命令类型
+	ErrUnknownCommand = errors.New("unknown command")
+	// ErrInvalidArguments 参数个数或格式不正确
+	ErrInvalidArguments = errors.New("invalid arguments")
+	// ErrSeatNotAllowed 调用者的席位不能使用该命令
+	ErrSeatNotAllowed = errors.New("command not allowed for this seat")
+	// ErrPhaseNotAllowed 当前阶段不能使用该命令
+	ErrPhaseNotAllowed = errors.New("command not allowed in current phase")
+	// ErrNotYourTurn 当前等待的决策不属于调用者
+	ErrNotYourTurn = errors.New("not your turn")
+	// ErrTargetNotFound 找不到命令指定的角色、位置、卡牌等
+	ErrTargetNotFound = errors.New("target not found")
+	// ErrInvalidTarget 目标不在当前决策的合法选项中
+	ErrInvalidTarget = errors.New("target not allowed")
+	// ErrNotSupported 引擎尚不支持该命令
+	ErrNotSupported = errors.New("command not supported")
+)
+
+// CommandError 命令执行失败时返回的错误，可通过 errors.Is 判断具体原因
+type CommandError struct {
+	Command CommandType
+	Err     error
+}
+
+func (e *CommandError) Error() string {
+	if e.Command == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Command, e.Err)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Command 表示一条解析后的命令
+type Command struct {
+	Type CommandType // 命令类型
+	Args []string    // 参数（已去除引号）
+	Raw  string      // 原始输入
+}
+
+// Arg 返回第 i 个参数，不存在时返回空字符串
+func (c *Command) Arg(i int) string {
+	if i < 0 || i >= len(c.Args) {
+		return ""
+	}
+	return c.Args[i]
+}
+
+// Parse 解析一行命令输入，支持单双引号包裹的参数及反斜杠转义
+// Example: place "goodwill+1" "Shrine Maiden"
+func Parse(line string) (*Command, error) {
+	tokens, err := tokenize(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrEmptyCommand
+	}
+
+	cmdType := CommandType(tokens[0])
+	spec, ok := Lookup(cmdType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCommand, tokens[0])
+	}
+
+	cmd := &Command{
+		Type: cmdType,
+		Args: tokens[1:],
+		Raw:  line,
+	}
+	if err = spec.checkArgs(cmd.Args); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+// tokenize 按空白切分输入，引号内的空白保留
+func tokenize(line string) ([]string, error) {
+	var (
+		tokens  []string
+		current strings.Builder
+		quote   rune
+		escaped bool
+		inToken bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+			inToken = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, ErrUnterminatedQuote
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
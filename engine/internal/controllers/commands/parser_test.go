@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+		err  error
+	}{
+		{name: "plain words", line: "place card1 School", want: []string{"place", "card1", "School"}},
+		{name: "extra whitespace", line: "  place\tcard1 \r\n School  ", want: []string{"place", "card1", "School"}},
+		{name: "empty line", line: "   ", want: nil},
+		{name: "double quotes keep spaces", line: `note "the boy is the key"`, want: []string{"note", "the boy is the key"}},
+		{name: "single quotes keep spaces", line: `note 'a b'`, want: []string{"note", "a b"}},
+		{name: "quotes inside a word", line: `say pre"fix suf"fix`, want: []string{"say", "prefix suffix"}},
+		{name: "other quote kind is literal", line: `note "it's fine"`, want: []string{"note", "it's fine"}},
+		{name: "empty quotes are an argument", line: `note ""`, want: []string{"note", ""}},
+		{name: "escaped space", line: `note a\ b`, want: []string{"note", "a b"}},
+		{name: "escaped quote", line: `note \"a`, want: []string{"note", `"a`}},
+		{name: "escape inside quotes", line: `note "say \"hi\""`, want: []string{"note", `say "hi"`}},
+		{name: "unterminated double quote", line: `note "abc`, err: ErrUnterminatedQuote},
+		{name: "unterminated single quote", line: `note 'abc`, err: ErrUnterminatedQuote},
+		{name: "trailing backslash", line: `note abc\`, err: ErrUnterminatedQuote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenize(tt.line)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("tokens = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantType CommandType
+		wantArgs []string
+		err      error
+	}{
+		{name: "command without arguments", line: "pass", wantType: CmdPassAction, wantArgs: []string{}},
+		{name: "quoted arguments", line: `place "card 1" 'School'`, wantType: CmdPlaceCard, wantArgs: []string{"card 1", "School"}},
+		{name: "unlimited arguments", line: "note the boy is the key", wantType: CmdMakeNote, wantArgs: []string{"the", "boy", "is", "the", "key"}},
+		{name: "optional argument", line: "timeSpiral ready", wantType: CmdSetupTimeSpiral, wantArgs: []string{"ready"}},
+		{name: "empty input", line: "", err: ErrEmptyCommand},
+		{name: "unknown command", line: "dance now", err: ErrUnknownCommand},
+		{name: "unterminated quote", line: `place "card1 School`, err: ErrUnterminatedQuote},
+		{name: "too few arguments", line: "place card1", err: ErrInvalidArguments},
+		{name: "too many arguments", line: "pass now please", err: ErrInvalidArguments},
+		{name: "number expected", line: "incidents first", err: ErrInvalidArguments},
+		{name: "unpaired guesses", line: "guess BoyStudent Killer GirlStudent", err: ErrInvalidArguments},
+		{name: "unknown time spiral action", line: "timeSpiral later", err: ErrInvalidArguments},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := Parse(tt.line)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if cmd.Type != tt.wantType {
+				t.Errorf("type = %s, want %s", cmd.Type, tt.wantType)
+			}
+			if !slices.Equal(cmd.Args, tt.wantArgs) {
+				t.Errorf("args = %q, want %q", cmd.Args, tt.wantArgs)
+			}
+			if cmd.Raw != tt.line {
+				t.Errorf("raw = %q, want %q", cmd.Raw, tt.line)
+			}
+		})
+	}
+}
+
+func TestCommandArg(t *testing.T) {
+	cmd := &Command{Type: CmdPlaceCard, Args: []string{"card1", "School"}}
+	for i, want := range []string{"card1", "School", ""} {
+		if got := cmd.Arg(i); got != want {
+			t.Errorf("Arg(%d) = %q, want %q", i, got, want)
+		}
+	}
+	if got := cmd.Arg(-1); got != "" {
+		t.Errorf("Arg(-1) = %q, want empty", got)
+	}
+}
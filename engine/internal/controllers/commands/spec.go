@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"tragedy-looper/engine/internal/models"
+)
+
+// unlimitedArgs 表示参数个数不设上限
+const unlimitedArgs = -1
+
+// Spec 描述一条命令的语法及调用限制
+type Spec struct {
+	Type    CommandType
+	Syntax  string // 语法说明
+	Summary string // 简要描述
+	MinArgs int    // 最少参数个数
+	MaxArgs int    // 最多参数个数，unlimitedArgs 表示不限
+
+	Seats      []models.Seat      // 允许调用的席位，为空表示不限
+	GamePhases []models.GamePhase // 允许调用的游戏阶段，为空表示不限
+	DayPhases  []models.DayPhase  // 允许调用的日阶段，为空表示不限
+
+	// validateArgs 额外的参数格式检查
+	validateArgs func(args []string) error
+}
+
+// AllowsSeat 检查席位是否可以调用该命令
+func (s *Spec) AllowsSeat(seat models.Seat) bool {
+	if len(s.Seats) == 0 {
+		return true
+	}
+	for _, allowed := range s.Seats {
+		if allowed == seat {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsPhase 检查当前阶段是否可以调用该命令
+func (s *Spec) AllowsPhase(gamePhase models.GamePhase, dayPhase models.DayPhase) bool {
+	if len(s.GamePhases) > 0 {
+		found := false
+		for _, allowed := range s.GamePhases {
+			if allowed == gamePhase {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(s.DayPhases) > 0 {
+		for _, allowed := range s.DayPhases {
+			if allowed == dayPhase {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (s *Spec) checkArgs(args []string) error {
+	if len(args) < s.MinArgs || (s.MaxArgs != unlimitedArgs && len(args) > s.MaxArgs) {
+		return fmt.Errorf("%w: %s (syntax: %s)", ErrInvalidArguments, s.Type, s.Syntax)
+	}
+	if s.validateArgs != nil {
+		if err := s.validateArgs(args); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArguments, s.Type, err)
+		}
+	}
+	return nil
+}
+
+// Lookup 根据命令类型获取命令说明
+func Lookup(cmdType CommandType) (*Spec, bool) {
+	spec, ok := specs[cmdType]
+	return spec, ok
+}
+
+// Specs 返回按名称排序的所有命令说明
+func Specs() []*Spec {
+	list := make([]*Spec, 0, len(specs))
+	for _, spec := range specs {
+		list = append(list, spec)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Type < list[j].Type
+	})
+	return list
+}
+
+var (
+	mastermindOnly   = []models.Seat{models.SeatMastermind}
+	protagonistsOnly = []models.Seat{models.SeatProtagonist}
+	playersOnly      = []models.Seat{models.SeatMastermind, models.SeatProtagonist}
+	inLoop           = []models.GamePhase{models.PhaseLoop}
+)
+
+var specs = map[CommandType]*Spec{}
+
+func register(spec *Spec) {
+	specs[spec.Type] = spec
+}
+
+func init() {
+	register(&Spec{Type: CmdSelectScript, Syntax: "selectScript <ScriptID>", Summary: "Select a script to play",
+		MinArgs: 1, MaxArgs: 1, Seats: mastermindOnly,
+		GamePhases: []models.GamePhase{models.PhaseGameStart, models.PhasePreparation, models.PhaseScriptSelection}})
+	register(&Spec{Type: CmdStartGame, Syntax: "start", Summary: "Start the game/loop with current setup",
+		Seats: mastermindOnly})
+	register(&Spec{Type: CmdPlaceCard, Syntax: "place <CardID> <LocationType|CharacterName>", Summary: "Place an action card on a character or location",
+		MinArgs: 2, MaxArgs: 2, Seats: playersOnly, GamePhases: inLoop,
+		DayPhases: []models.DayPhase{models.PhaseMastermindAction, models.PhaseProtagonistsAction}})
+	register(&Spec{Type: CmdPassAction, Syntax: "pass", Summary: "Skip current action/do nothing this turn",
+		Seats: playersOnly})
+	register(&Spec{Type: CmdShowCards, Syntax: "cards", Summary: "View your hand of action cards",
+		Seats: playersOnly})
+	register(&Spec{Type: CmdShowBoard, Syntax: "board", Summary: "View the current board state with all characters and locations"})
+	register(&Spec{Type: CmdStatus, Syntax: "status <CharacterName|LocationType>", Summary: "Check status of a character or location",
+		MinArgs: 1, MaxArgs: 1})
+	register(&Spec{Type: CmdViewRules, Syntax: "rules [RoleName|PlotName]", Summary: "View current script rules or role information",
+		MaxArgs: 1})
+	register(&Spec{Type: CmdViewIncidents, Syntax: "incidents [Day]", Summary: "View incidents that have occurred",
+		MaxArgs: 1, validateArgs: optionalNumber})
+	register(&Spec{Type: CmdViewHistory, Syntax: "history [Day]", Summary: "View history log of events",
+		MaxArgs: 1, validateArgs: optionalNumber})
+	register(&Spec{Type: CmdUseGoodwill, Syntax: "goodwill <CharacterName> [TargetName]", Summary: "Use a character's goodwill ability",
+		MinArgs: 1, MaxArgs: 2, Seats: protagonistsOnly, GamePhases: inLoop,
+		DayPhases: []models.DayPhase{models.PhaseLeaderGoodwill}})
+	register(&Spec{Type: CmdRefuseGoodwill, Syntax: "refuse", Summary: "Refuse the goodwill ability the leader is using",
+		Seats: mastermindOnly, GamePhases: inLoop,
+		DayPhases: []models.DayPhase{models.PhaseLeaderGoodwill}})
+	register(&Spec{Type: CmdSelectChar, Syntax: "selectChar <CharacterName>", Summary: "Select a character as target for an action or ability",
+		MinArgs: 1, MaxArgs: 1, Seats: playersOnly})
+	register(&Spec{Type: CmdSelectLocation, Syntax: "selectLoc <LocationType>", Summary: "Select a location as target",
+		MinArgs: 1, MaxArgs: 1, Seats: playersOnly})
+	register(&Spec{Type: CmdFinalGuess, Syntax: "guess <CharacterName> <RoleName> [CharacterName RoleName...]", Summary: "Make final guess about character roles",
+		MinArgs: 2, MaxArgs: unlimitedArgs, Seats: protagonistsOnly,
+		GamePhases: []models.GamePhase{models.PhaseFinalGuess}, validateArgs: pairedArgs})
+	register(&Spec{Type: CmdNextPhase, Syntax: "next", Summary: "Proceed to next game phase",
+		Seats: playersOnly})
+	register(&Spec{Type: CmdEndTurn, Syntax: "end", Summary: "End current turn",
+		Seats: playersOnly})
+	register(&Spec{Type: CmdMakeNote, Syntax: "note <Text>", Summary: "Post a note to the shared board during the Time Spiral",
+		MinArgs: 1, MaxArgs: unlimitedArgs, Seats: protagonistsOnly, GamePhases: inLoop})
+	register(&Spec{Type: CmdHelp, Syntax: "help [CommandName]", Summary: "Display help information",
+		MaxArgs: 1})
+	register(&Spec{Type: CmdQuit, Syntax: "quit", Summary: "Exit the game"})
+	register(&Spec{Type: CmdMoveCharacter, Syntax: "move <CharacterName> <LocationType>", Summary: "Move a character to a different location",
+		MinArgs: 2, MaxArgs: 2, Seats: mastermindOnly, GamePhases: inLoop})
+	register(&Spec{Type: CmdCheckParanoia, Syntax: "paranoia", Summary: "Check characters at or above paranoia limit"})
+	register(&Spec{Type: CmdCheckIntrigue, Syntax: "intrigue", Summary: "Check intrigue counters on characters/locations"})
+	register(&Spec{Type: CmdTriggerIncident, Syntax: "triggerIncident <IncidentName> [TargetName]", Summary: "Trigger a scheduled incident",
+		MinArgs: 1, MaxArgs: 2, Seats: mastermindOnly, GamePhases: inLoop,
+		DayPhases: []models.DayPhase{models.PhaseIncidents}})
+	register(&Spec{Type: CmdUseMastermindAbility, Syntax: "mastermindAbility <CharacterName> [TargetName|LocationName]", Summary: "Use a character's mastermind ability",
+		MinArgs: 1, MaxArgs: 2, Seats: mastermindOnly, GamePhases: inLoop,
+		DayPhases: []models.DayPhase{models.PhaseMastermindAbilities}})
+	register(&Spec{Type: CmdDayStart, Syntax: "dayStart", Summary: "Perform day start actions",
+		Seats: mastermindOnly, GamePhases: inLoop})
+	register(&Spec{Type: CmdDayEnd, Syntax: "dayEnd", Summary: "Perform day end actions",
+		Seats: mastermindOnly, GamePhases: inLoop})
+	register(&Spec{Type: CmdLoopStart, Syntax: "loopStart", Summary: "Perform loop start actions",
+		Seats: mastermindOnly, GamePhases: inLoop})
+	register(&Spec{Type: CmdLoopEnd, Syntax: "loopEnd", Summary: "Perform loop end actions and check loss conditions",
+		Seats: mastermindOnly, GamePhases: inLoop})
+	register(&Spec{Type: CmdRevealRole, Syntax: "revealRole <CharacterName>", Summary: "Reveal a character's role",
+		MinArgs: 1, MaxArgs: 1, Seats: mastermindOnly})
+	register(&Spec{Type: CmdKillCharacter, Syntax: "kill <CharacterName>", Summary: "Kill a character",
+		MinArgs: 1, MaxArgs: 1, Seats: mastermindOnly, GamePhases: inLoop})
+	register(&Spec{Type: CmdKillProtagonists, Syntax: "killProtagonists", Summary: "Kill the protagonists",
+		Seats: mastermindOnly, GamePhases: inLoop})
+	register(&Spec{Type: CmdCheckLossCondition, Syntax: "checkLoss <PlotName>", Summary: "Check if a specific loss condition is met",
+		MinArgs: 1, MaxArgs: 1, Seats: mastermindOnly})
+	register(&Spec{Type: CmdSetupTimeSpiral, Syntax: "timeSpiral [ready]", Summary: "View the Time Spiral notes or signal ready",
+		MaxArgs: 1, Seats: protagonistsOnly, validateArgs: timeSpiralArgs})
+	register(&Spec{Type: CmdChangeLeader, Syntax: "changeLeader <PlayerName>", Summary: "Change the current leader among protagonists",
+		MinArgs: 1, MaxArgs: 1, Seats: protagonistsOnly})
+}
+
+// optionalNumber 检查可选参数是否为数字
+func optionalNumber(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return fmt.Errorf("%q is not a number", args[0])
+	}
+	return nil
+}
+
+// timeSpiralArgs 检查时间螺旋命令的可选参数
+func timeSpiralArgs(args []string) error {
+	if len(args) == 1 && args[0] != "ready" {
+		return fmt.Errorf("unknown time spiral action %q", args[0])
+	}
+	return nil
+}
+
+// pairedArgs 检查参数是否成对出现
+func pairedArgs(args []string) error {
+	if len(args)%2 != 0 {
+		return fmt.Errorf("arguments must come in pairs")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"testing"
+	"tragedy-looper/engine/internal/models"
+)
+
+func TestSpecAllowsSeat(t *testing.T) {
+	tests := []struct {
+		cmd  CommandType
+		seat models.Seat
+		want bool
+	}{
+		{CmdRefuseGoodwill, models.SeatMastermind, true},
+		{CmdRefuseGoodwill, models.SeatProtagonist, false},
+		{CmdUseGoodwill, models.SeatProtagonist, true},
+		{CmdUseGoodwill, models.SeatMastermind, false},
+		{CmdPlaceCard, models.SeatMastermind, true},
+		{CmdPlaceCard, models.SeatProtagonist, true},
+		{CmdPlaceCard, models.SeatSpectator, false},
+		{CmdPassAction, models.SeatSpectator, false},
+		{CmdShowBoard, models.SeatSpectator, true},
+		{CmdStatus, models.SeatSpectator, true},
+		{CmdFinalGuess, models.SeatMastermind, false},
+		{CmdSetupTimeSpiral, models.SeatMastermind, false},
+		{CmdMakeNote, models.SeatProtagonist, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.cmd)+"/"+string(tt.seat), func(t *testing.T) {
+			spec, ok := Lookup(tt.cmd)
+			if !ok {
+				t.Fatalf("no spec for %s", tt.cmd)
+			}
+			if got := spec.AllowsSeat(tt.seat); got != tt.want {
+				t.Errorf("AllowsSeat(%s) = %v, want %v", tt.seat, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecAllowsPhase(t *testing.T) {
+	tests := []struct {
+		cmd       CommandType
+		gamePhase models.GamePhase
+		dayPhase  models.DayPhase
+		want      bool
+	}{
+		{CmdPlaceCard, models.PhaseLoop, models.PhaseMastermindAction, true},
+		{CmdPlaceCard, models.PhaseLoop, models.PhaseProtagonistsAction, true},
+		{CmdPlaceCard, models.PhaseLoop, models.PhaseIncidents, false},
+		{CmdPlaceCard, models.PhaseFinalGuess, models.PhaseMastermindAction, false},
+		{CmdUseGoodwill, models.PhaseLoop, models.PhaseLeaderGoodwill, true},
+		{CmdUseGoodwill, models.PhaseLoop, models.PhaseDayEnd, false},
+		{CmdRefuseGoodwill, models.PhaseLoop, models.PhaseLeaderGoodwill, true},
+		{CmdUseMastermindAbility, models.PhaseLoop, models.PhaseMastermindAbilities, true},
+		{CmdUseMastermindAbility, models.PhaseLoop, models.PhaseLeaderGoodwill, false},
+		{CmdFinalGuess, models.PhaseFinalGuess, "", true},
+		{CmdFinalGuess, models.PhaseLoop, models.PhaseDayEnd, false},
+		{CmdMakeNote, models.PhaseLoop, "", true},
+		{CmdMakeNote, models.PhaseGameEnd, "", false},
+		{CmdSelectScript, models.PhaseScriptSelection, "", true},
+		{CmdSelectScript, models.PhaseLoop, models.PhaseDayStart, false},
+		{CmdShowBoard, models.PhaseGameEnd, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.cmd)+"/"+string(tt.gamePhase)+"/"+string(tt.dayPhase), func(t *testing.T) {
+			spec, ok := Lookup(tt.cmd)
+			if !ok {
+				t.Fatalf("no spec for %s", tt.cmd)
+			}
+			if got := spec.AllowsPhase(tt.gamePhase, tt.dayPhase); got != tt.want {
+				t.Errorf("AllowsPhase(%s, %s) = %v, want %v", tt.gamePhase, tt.dayPhase, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecsSorted(t *testing.T) {
+	list := Specs()
+	if len(list) != len(specs) {
+		t.Fatalf("Specs() returned %d specs, want %d", len(list), len(specs))
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i-1].Type >= list[i].Type {
+			t.Errorf("specs not sorted: %s before %s", list[i-1].Type, list[i].Type)
+		}
+	}
+}
@@ -57,6 +57,10 @@ const (
 	// Example: goodwill "Shrine Maiden" "Student"
 	CmdUseGoodwill CommandType = "goodwill"
 
+	// CmdRefuseGoodwill - Refuse the goodwill ability the leader is using (Mastermind only)
+	// Syntax: refuse
+	CmdRefuseGoodwill CommandType = "refuse"
+
 	// CmdSelectChar - Select a character as target for an action or ability
 	// Syntax: selectChar <CharacterName>
 	// Example: selectChar "Office Worker"
@@ -80,7 +84,7 @@ const (
 	// Syntax: end
 	CmdEndTurn CommandType = "end"
 
-	// CmdMakeNote - Add personal note/marking
+	// CmdMakeNote - Post a note to the protagonists' shared board during the Time Spiral (Protagonists only)
 	// Syntax: note <Text>
 	// Example: note "I suspect the Student is the Key Person"
 	CmdMakeNote CommandType = "note"
@@ -154,8 +158,9 @@ const (
 	// Example: checkLoss "Murder Plan"
 	CmdCheckLossCondition CommandType = "checkLoss"
 
-	// CmdSetupTimeSpiral - Set up a time spiral discussion between loops (Protagonists only)
-	// Syntax: timeSpiral
+	// CmdSetupTimeSpiral - View the Time Spiral notes board or signal ready to end the discussion (Protagonists only)
+	// Syntax: timeSpiral [ready]
+	// Example: timeSpiral ready
 	CmdSetupTimeSpiral CommandType = "timeSpiral"
 
 	// CmdChangeLeader - Change the current leader among protagonists
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+	"tragedy-looper/engine/internal/models"
+)
+
+// 决策请求只携带决策者席位可见的 View，不暴露 GameState。
+// 发给主角方的请求以名称引用角色和位置，不携带角色指针。
+
+// PlaceCardsRequest 放置行动卡的决策请求
+type PlaceCardsRequest struct {
+	View    *models.GameView
+	Player  models.Player      // 需要放置卡牌的玩家
+	Hand    []models.Card      // 可用的手牌
+	Targets []models.TargetRef // 可放置的目标
+	Count   int                // 需要放置的卡牌数量
+
+	state *models.GameState
+}
+
+// CanPlace 检查卡牌能否放置在目标上，不合法时返回 *models.PlacementError
+func (r *PlaceCardsRequest) CanPlace(card models.Card, target models.TargetRef) error {
+	resolved := r.state.ResolveTarget(target)
+	if resolved == nil {
+		return &models.PlacementError{Card: card, Err: fmt.Errorf("%w: unknown target %q", models.ErrInvalidPlacement, target)}
+	}
+	return r.state.Board.CheckPlacement(resolved, card)
+}
+
+// CardChoice 决策者选择的一次行动卡放置
+type CardChoice struct {
+	Card   models.Card
+	Target models.TargetRef
+}
+
+// AbilityTargetRequest 角色身份能力的目标决策请求
+type AbilityTargetRequest struct {
+	View      *models.GameView
+	Character *models.Character   // 能力所属角色
+	Ability   models.RoleAbility  // 将要发动的能力
+	Options   []models.TargetType // 合法目标
+	Optional  bool                // 是否可以放弃发动（返回 nil）
+}
+
+// IncidentTargetRequest 事件目标的决策请求
+type IncidentTargetRequest struct {
+	View     *models.GameView
+	Incident models.Incident
+	Culprit  *models.Character   // 事件当事人
+	Options  []models.TargetType // 合法目标
+	Chosen   []models.TargetType // 多目标事件此前已经选择的目标
+}
+
+// GoodwillOption 一个可发动的好感度能力
+type GoodwillOption struct {
+	Character models.CharacterName
+	Ability   *models.CharacterAbilityData
+	Targets   []models.TargetRef // 能力的合法目标
+	Target    models.TargetRef   // 领袖选择的目标，回答时填写
+}
+
+// GoodwillAbilityRequest 领袖选择好感度能力的决策请求
+type GoodwillAbilityRequest struct {
+	View    *models.GameView
+	Leader  *models.Protagonist
+	Options []GoodwillOption // 满足好感度条件的能力
+}
+
+// GoodwillRefusalRequest 幕后主使是否拒绝好感度能力的决策请求
+type GoodwillRefusalRequest struct {
+	View      *models.GameView
+	Character *models.Character
+	Ability   *models.CharacterAbilityData
+}
+
+// FinalGuessRequest 最终猜测的决策请求
+type FinalGuessRequest struct {
+	View       *models.GameView
+	Characters []models.CharacterName // 需要猜测身份的角色
+	Roles      []models.RoleType      // 剧本中可能出现的身份
+}
+
+// TimeSpiralRequest 时间螺旋讨论的决策请求，回答为准备完毕的主角ID列表
+type TimeSpiralRequest struct {
+	View       *models.GameView
+	TimeSpiral *models.TimeSpiral
+	Deadline   time.Time // 讨论结束时间，不限时为零值
+}
+
+// MastermindDecider 幕后主使在各决策点的选择
+type MastermindDecider interface {
+	// PlaceMastermindCards 选择要放置的行动卡及目标
+	PlaceMastermindCards(req *PlaceCardsRequest) ([]CardChoice, error)
+	// ChooseAbilityTarget 选择能力目标，可选能力返回 nil 表示不发动
+	ChooseAbilityTarget(req *AbilityTargetRequest) (models.TargetType, error)
+	// ChooseIncidentTarget 选择事件目标
+	ChooseIncidentTarget(req *IncidentTargetRequest) (models.TargetType, error)
+	// RefuseGoodwill 决定是否拒绝好感度能力
+	RefuseGoodwill(req *GoodwillRefusalRequest) (bool, error)
+}
+
+// ProtagonistDecider 主角方在各决策点的选择
+type ProtagonistDecider interface {
+	// PlaceProtagonistCard 为一名主角选择要放置的行动卡及目标
+	PlaceProtagonistCard(req *PlaceCardsRequest) (CardChoice, error)
+	// ChooseGoodwillAbility 选择要发动的好感度能力，返回 nil 表示不发动
+	ChooseGoodwillAbility(req *GoodwillAbilityRequest) (*GoodwillOption, error)
+	// MakeFinalGuess 给出每个角色的身份猜测
+	MakeFinalGuess(req *FinalGuessRequest) (map[models.CharacterName]models.RoleType, error)
+	// DiscussTimeSpiral 在时间螺旋中讨论，返回准备完毕的主角ID
+	DiscussTimeSpiral(req *TimeSpiralRequest) ([]string, error)
+}
+
+// DecisionProvider 同时为双方做出选择，人类玩家、AI 和测试脚本都通过它驱动引擎
+type DecisionProvider interface {
+	MastermindDecider
+	ProtagonistDecider
+}
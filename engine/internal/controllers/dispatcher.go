@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"tragedy-looper/engine/internal/controllers/commands"
+	"tragedy-looper/engine/internal/models"
+)
+
+// CommandResult 命令执行结果
+type CommandResult struct {
+	Command commands.CommandType // 执行的命令
+	Message string               // 面向玩家的结果描述
+	Data    any                  // 结构化的结果数据
+	Pending *PendingDecision     // 命令执行后等待调用者席位回答的决策
+	Waiting models.Seat          // 引擎等待其他席位决策时为该席位，此时 Pending 为空
+}
+
+// CommandContext 命令执行时的上下文
+type CommandContext struct {
+	Game    *GameController
+	Player  models.Player // 发出命令的玩家
+	Seat    models.Seat   // 玩家所在席位
+	Command *commands.Command
+}
+
+// CommandHandler 命令处理函数
+type CommandHandler func(ctx *CommandContext) (*CommandResult, error)
+
+// CommandDispatcher 解析并执行玩家命令
+type CommandDispatcher struct {
+	logging  *zap.Logger
+	game     *GameController
+	handlers map[commands.CommandType]CommandHandler
+}
+
+// NewCommandDispatcher 创建命令分发器并注册默认的命令处理函数
+func NewCommandDispatcher(gc *GameController) *CommandDispatcher {
+	dispatcher := &CommandDispatcher{
+		logging:  gc.logging,
+		game:     gc,
+		handlers: make(map[commands.CommandType]CommandHandler),
+	}
+	registerDefaultHandlers(dispatcher)
+	return dispatcher
+}
+
+// Register 注册（或替换）某个命令类型的处理函数
+func (d *CommandDispatcher) Register(cmdType commands.CommandType, handler CommandHandler) error {
+	if _, ok := commands.Lookup(cmdType); !ok {
+		return &commands.CommandError{Command: cmdType, Err: commands.ErrUnknownCommand}
+	}
+	d.handlers[cmdType] = handler
+	return nil
+}
+
+// Dispatch 解析一行命令并以指定玩家的身份执行
+func (d *CommandDispatcher) Dispatch(player models.Player, line string) (*CommandResult, error) {
+	cmd, err := commands.Parse(line)
+	if err != nil {
+		d.logging.Debug("Failed to parse command",
+			zap.String("input", line),
+			zap.Error(err))
+		return nil, &commands.CommandError{Err: err}
+	}
+	return d.Execute(player, cmd)
+}
+
+// Execute 以指定玩家的身份执行一条已解析的命令
+func (d *CommandDispatcher) Execute(player models.Player, cmd *commands.Command) (*CommandResult, error) {
+	spec, ok := commands.Lookup(cmd.Type)
+	if !ok {
+		return nil, &commands.CommandError{Command: cmd.Type, Err: commands.ErrUnknownCommand}
+	}
+
+	seat := models.SeatOf(player)
+	if !spec.AllowsSeat(seat) {
+		d.logging.Debug("Command rejected for seat",
+			zap.String("command", string(cmd.Type)),
+			zap.String("seat", string(seat)))
+		return nil, &commands.CommandError{Command: cmd.Type, Err: commands.ErrSeatNotAllowed}
+	}
+
+	// 时间螺旋到期后即使没有人推进引擎也要关闭，否则待回答的决策会一直阻止推进
+	d.game.checkTimeSpiral()
+
+	state := d.game.state
+	if !spec.AllowsPhase(state.CurrentGamePhase, state.CurrentDayPhase) {
+		d.logging.Debug("Command rejected for phase",
+			zap.String("command", string(cmd.Type)),
+			zap.String("gamePhase", string(state.CurrentGamePhase)),
+			zap.String("dayPhase", string(state.CurrentDayPhase)))
+		return nil, &commands.CommandError{
+			Command: cmd.Type,
+			Err: fmt.Errorf("%w: %s/%s", commands.ErrPhaseNotAllowed,
+				state.CurrentGamePhase, state.CurrentDayPhase),
+		}
+	}
+
+	handler, ok := d.handlers[cmd.Type]
+	if !ok {
+		return nil, &commands.CommandError{Command: cmd.Type, Err: commands.ErrNotSupported}
+	}
+
+	result, err := handler(&CommandContext{
+		Game:    d.game,
+		Player:  player,
+		Seat:    seat,
+		Command: cmd,
+	})
+	if err != nil {
+		d.logging.Debug("Command failed",
+			zap.String("command", string(cmd.Type)),
+			zap.Strings("args", cmd.Args),
+			zap.Error(err))
+		var cmdErr *commands.CommandError
+		if errors.As(err, &cmdErr) {
+			return nil, cmdErr
+		}
+		return nil, &commands.CommandError{Command: cmd.Type, Err: err}
+	}
+	if result.Command == "" {
+		result.Command = cmd.Type
+	}
+	// 其他席位的决策请求包含该席位的视图，只告诉调用者在等待哪个席位
+	if result.Pending = d.game.Pending(seat); result.Pending == nil {
+		result.Waiting = d.game.WaitingOn()
+	}
+
+	d.logging.Debug("Command executed",
+		zap.String("command", string(cmd.Type)),
+		zap.String("seat", string(seat)),
+		zap.Strings("args", cmd.Args))
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"tragedy-looper/engine/cmd/first_steps"
+	"tragedy-looper/engine/internal/controllers/commands"
+	"tragedy-looper/engine/internal/models"
+)
+
+// newTestGame 创建 First Steps 第一个剧本的游戏并推进到第一个决策
+func newTestGame(t *testing.T) *GameController {
+	t.Helper()
+	gc := NewGameController(zap.NewNop(), first_steps.NewFirstSteps1())
+	if err := gc.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gc.Advance(); err != nil {
+		t.Fatal(err)
+	}
+	return gc
+}
+
+func TestDispatchRouting(t *testing.T) {
+	tests := []struct {
+		name string
+		// seat 发出命令的席位
+		seat models.Seat
+		line string
+		err  error
+		// waiting 命令执行后等待的其他席位
+		waiting models.Seat
+	}{
+		{name: "malformed input", seat: models.SeatMastermind, line: `place "card`, err: commands.ErrUnterminatedQuote},
+		{name: "unknown command", seat: models.SeatMastermind, line: "dance", err: commands.ErrUnknownCommand},
+		{name: "spectator cannot pass", seat: models.SeatSpectator, line: "pass", err: commands.ErrSeatNotAllowed},
+		{name: "protagonist cannot refuse", seat: models.SeatProtagonist, line: "refuse", err: commands.ErrSeatNotAllowed},
+		{name: "seat is checked before phase", seat: models.SeatMastermind, line: "guess BoyStudent Killer", err: commands.ErrSeatNotAllowed},
+		{name: "guess outside the final guess", seat: models.SeatProtagonist, line: "guess BoyStudent Killer", err: commands.ErrPhaseNotAllowed},
+		{name: "goodwill outside its day phase", seat: models.SeatProtagonist, line: "goodwill BoyStudent", err: commands.ErrPhaseNotAllowed},
+		{name: "removed move handler", seat: models.SeatMastermind, line: "move BoyStudent School", err: commands.ErrNotSupported},
+		{name: "removed kill handler", seat: models.SeatMastermind, line: "kill BoyStudent", err: commands.ErrNotSupported},
+		{name: "protagonist answers the mastermind's decision", seat: models.SeatProtagonist, line: "pass", err: commands.ErrNotYourTurn},
+		{name: "next while a decision is pending", seat: models.SeatProtagonist, line: "next", err: commands.ErrPhaseNotAllowed},
+		{name: "spectator views the board", seat: models.SeatSpectator, line: "board", waiting: models.SeatMastermind},
+		{name: "protagonist views their hand", seat: models.SeatProtagonist, line: "cards", waiting: models.SeatMastermind},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc := newTestGame(t)
+			d := NewCommandDispatcher(gc)
+			var player models.Player
+			switch tt.seat {
+			case models.SeatMastermind:
+				player = gc.state.Mastermind
+			case models.SeatProtagonist:
+				player = gc.state.Protagonists.GetLeader()
+			}
+
+			result, err := d.Dispatch(player, tt.line)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				var cmdErr *commands.CommandError
+				if !errors.As(err, &cmdErr) {
+					t.Errorf("err %T is not a CommandError", err)
+				}
+				return
+			}
+			if result.Pending != nil {
+				t.Errorf("pending decision %s leaked to %s", result.Pending.Kind, tt.seat)
+			}
+			if result.Waiting != tt.waiting {
+				t.Errorf("waiting = %q, want %q", result.Waiting, tt.waiting)
+			}
+		})
+	}
+}
+
+func TestDispatchCustomHandler(t *testing.T) {
+	gc := newTestGame(t)
+	d := NewCommandDispatcher(gc)
+
+	var called *CommandContext
+	err := d.Register(commands.CmdQuit, func(ctx *CommandContext) (*CommandResult, error) {
+		called = ctx
+		return &CommandResult{Message: "bye"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := d.Dispatch(gc.state.Mastermind, "quit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if called == nil || called.Seat != models.SeatMastermind || called.Command.Type != commands.CmdQuit {
+		t.Fatalf("handler called with %+v", called)
+	}
+	if result.Command != commands.CmdQuit || result.Message != "bye" {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Pending == nil || result.Pending.Seat != models.SeatMastermind {
+		t.Error("the mastermind's own pending decision should be attached")
+	}
+
+	if err := d.Register("dance", nil); !errors.Is(err, commands.ErrUnknownCommand) {
+		t.Errorf("register unknown command: err = %v", err)
+	}
+}
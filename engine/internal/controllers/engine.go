@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"tragedy-looper/engine/internal/models"
+)
+
+// DecisionKind 决策点类型
+type DecisionKind string
+
+const (
+	DecisionPlaceMastermindCards DecisionKind = "PlaceMastermindCards" // 幕后主使放置行动卡
+	DecisionPlaceProtagonistCard DecisionKind = "PlaceProtagonistCard" // 主角放置行动卡
+	DecisionAbilityTarget        DecisionKind = "AbilityTarget"        // 选择能力目标
+	DecisionIncidentTarget       DecisionKind = "IncidentTarget"       // 选择事件目标
+	DecisionGoodwillAbility      DecisionKind = "GoodwillAbility"      // 领袖选择好感度能力
+	DecisionGoodwillRefusal      DecisionKind = "GoodwillRefusal"      // 幕后主使决定是否拒绝
+	DecisionFinalGuess           DecisionKind = "FinalGuess"           // 最终猜测
+	DecisionTimeSpiral           DecisionKind = "TimeSpiral"           // 时间螺旋讨论，等待主角准备完毕
+)
+
+var (
+	// ErrNoPendingDecision 当前没有等待回答的决策
+	ErrNoPendingDecision = errors.New("no pending decision")
+	// ErrInvalidAnswer 回答的类型或内容不合法
+	ErrInvalidAnswer = errors.New("invalid answer")
+	// ErrGameNotStarted 游戏尚未开始
+	ErrGameNotStarted = errors.New("game not started")
+)
+
+// PendingDecision 引擎暂停时等待玩家回答的决策
+//
+// Request 为对应的请求结构（如 *PlaceCardsRequest），其中包含合法选项；
+// 回答的类型与 DecisionProvider 中对应方法的返回值一致。
+type PendingDecision struct {
+	Kind    DecisionKind
+	Seat    models.Seat   // 需要做决策的席位
+	Player  models.Player // 需要做决策的玩家
+	Request any           // 决策请求及合法选项
+
+	resolve func(answer any) error
+}
+
+// engineStep 状态机中的一个步骤
+type engineStep struct {
+	name string
+	run  func() error
+}
+
+// Start 初始化游戏并进入第一个状态，之后通过 Advance/Answer 推进
+func (gc *GameController) Start() error {
+	gc.logging.Debug("Game initialization started in controller")
+
+	if err := gc.setupGame(); err != nil {
+		gc.logging.Error("Game setup failed", zap.Error(err))
+		return err
+	}
+
+	gc.logging.Debug("Enter the main game loop from the controller")
+	gc.pushBack(engineStep{name: "GameLoop", run: gc.startGameLoop})
+	return nil
+}
+
+// Advance 推进引擎直到需要玩家决策或游戏结束
+//
+// 返回等待回答的决策；游戏结束时返回 nil。
+func (gc *GameController) Advance() (*PendingDecision, error) {
+	if gc.state.Script == nil {
+		return nil, ErrGameNotStarted
+	}
+	for {
+		gc.checkTimeSpiral()
+		if err := gc.checkLoopEnd(); err != nil {
+			return nil, err
+		}
+		if gc.pending != nil || len(gc.steps) == 0 {
+			break
+		}
+
+		step := gc.steps[0]
+		gc.steps = gc.steps[1:]
+
+		if err := step.run(); err != nil {
+			gc.logging.Error("Engine step failed",
+				zap.String("step", step.name),
+				zap.Error(err))
+			return nil, fmt.Errorf("%s: %w", step.name, err)
+		}
+
+		if gc.state.CurrentGamePhase == models.PhaseGameEnd {
+			gc.steps = nil
+		}
+	}
+	return gc.pending, nil
+}
+
+// Pending 返回等待指定席位回答的决策，决策属于其他席位或没有等待的决策时返回 nil
+//
+// 决策请求包含决策者席位的视图，不能交给其他席位。
+func (gc *GameController) Pending(seat models.Seat) *PendingDecision {
+	if gc.pending == nil || gc.pending.Seat != seat {
+		return nil
+	}
+	return gc.pending
+}
+
+// WaitingOn 返回引擎正在等待决策的席位，没有等待的决策时为空
+func (gc *GameController) WaitingOn() models.Seat {
+	if gc.pending == nil {
+		return ""
+	}
+	return gc.pending.Seat
+}
+
+// Answer 回答当前等待的决策，回答不合法时决策保持等待状态且游戏状态不变，可以重新回答
+func (gc *GameController) Answer(answer any) error {
+	pending := gc.pending
+	if pending == nil {
+		return ErrNoPendingDecision
+	}
+
+	gc.pending = nil
+	if err := pending.resolve(answer); err != nil {
+		gc.logging.Debug("Answer rejected",
+			zap.String("decision", string(pending.Kind)),
+			zap.Error(err))
+		if gc.pending == nil {
+			gc.pending = pending
+		}
+		return err
+	}
+	return nil
+}
+
+// IsOver 游戏是否已经结束
+func (gc *GameController) IsOver() bool {
+	return gc.state.CurrentGamePhase == models.PhaseGameEnd
+}
+
+// ask 暂停引擎，等待玩家回答决策
+func (gc *GameController) ask(kind DecisionKind, player models.Player, request any, resolve func(answer any) error) {
+	gc.logging.Debug("Waiting for decision",
+		zap.String("decision", string(kind)),
+		zap.String("seat", string(models.SeatOf(player))))
+	gc.pending = &PendingDecision{
+		Kind:    kind,
+		Seat:    models.SeatOf(player),
+		Player:  player,
+		Request: request,
+		resolve: resolve,
+	}
+}
+
+// checkLoopEnd 处理新死亡角色的死亡时能力；循环提前结束时跳过剩余阶段直接进入循环结束
+func (gc *GameController) checkLoopEnd() error {
+	if err := gc.handleDeaths(); err != nil {
+		return err
+	}
+	if !gc.state.LoopEndedEarly() || gc.state.CurrentLoopPhase == models.PhaseLoopEnd {
+		return nil
+	}
+	for i, step := range gc.steps {
+		if step.name != string(models.PhaseLoopEnd) {
+			continue
+		}
+		gc.logging.Debug("Skip the remaining phases of the loop",
+			zap.String("cause", string(gc.state.LoopEnd.Cause)),
+			zap.Int("skipped", i))
+		gc.steps = gc.steps[i:]
+		gc.pending = nil
+		return nil
+	}
+	return nil
+}
+
+// handleDeaths 对新死亡的角色发动死亡时能力，例如关键人物死亡导致循环结束
+func (gc *GameController) handleDeaths() error {
+	for _, death := range gc.state.TakePendingDeaths() {
+		character := death.Character
+		if character.Role() == nil {
+			continue
+		}
+		for _, ability := range character.Role().Abilities {
+			if ability.GetTiming() != models.RoleTimingCharacterDeath {
+				continue
+			}
+			ok, err := ability.IsTriggerable(gc.state, character)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			gc.logging.Debug("Death ability triggered",
+				zap.String("character", string(character.Name)),
+				zap.String("role", string(ability.RoleType())),
+				zap.String("cause", string(death.Cause)))
+			if err = gc.useRoleAbility(character, ability, character); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pushBack 将步骤追加到队列末尾
+func (gc *GameController) pushBack(steps ...engineStep) {
+	gc.steps = append(gc.steps, steps...)
+}
+
+// pushFront 将步骤按顺序插入到队列开头
+func (gc *GameController) pushFront(steps ...engineStep) {
+	gc.steps = append(append([]engineStep(nil), steps...), gc.steps...)
+}
+
+// askProvider 使用已配置的决策者回答决策
+func (gc *GameController) askProvider(pending *PendingDecision) (any, error) {
+	switch pending.Kind {
+	case DecisionPlaceMastermindCards:
+		return gc.mastermindDecider.PlaceMastermindCards(pending.Request.(*PlaceCardsRequest))
+	case DecisionPlaceProtagonistCard:
+		return gc.protagonistDecider.PlaceProtagonistCard(pending.Request.(*PlaceCardsRequest))
+	case DecisionAbilityTarget:
+		return gc.mastermindDecider.ChooseAbilityTarget(pending.Request.(*AbilityTargetRequest))
+	case DecisionIncidentTarget:
+		return gc.mastermindDecider.ChooseIncidentTarget(pending.Request.(*IncidentTargetRequest))
+	case DecisionGoodwillAbility:
+		return gc.protagonistDecider.ChooseGoodwillAbility(pending.Request.(*GoodwillAbilityRequest))
+	case DecisionTimeSpiral:
+		return gc.protagonistDecider.DiscussTimeSpiral(pending.Request.(*TimeSpiralRequest))
+	case DecisionGoodwillRefusal:
+		return gc.mastermindDecider.RefuseGoodwill(pending.Request.(*GoodwillRefusalRequest))
+	case DecisionFinalGuess:
+		return gc.protagonistDecider.MakeFinalGuess(pending.Request.(*FinalGuessRequest))
+	default:
+		return nil, fmt.Errorf("unknown decision kind: %s", pending.Kind)
+	}
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"tragedy-looper/engine/internal/models"
+)
+
+// FirstOptionProvider 总是选择第一个合法选项的简单决策者，用于无人值守的对局和调试
+type FirstOptionProvider struct{}
+
+// NewFirstOptionProvider 创建新的简单决策者
+func NewFirstOptionProvider() *FirstOptionProvider {
+	return &FirstOptionProvider{}
+}
+
+func (p *FirstOptionProvider) PlaceMastermindCards(req *PlaceCardsRequest) ([]CardChoice, error) {
+	placements := make([]CardChoice, 0, req.Count)
+	used := make(map[models.TargetRef]bool)
+	for _, card := range req.Hand {
+		if len(placements) == req.Count {
+			break
+		}
+		for _, target := range req.Targets {
+			if used[target] || req.CanPlace(card, target) != nil {
+				continue
+			}
+			used[target] = true
+			placements = append(placements, CardChoice{Card: card, Target: target})
+			break
+		}
+	}
+	return placements, nil
+}
+
+func (p *FirstOptionProvider) ChooseAbilityTarget(req *AbilityTargetRequest) (models.TargetType, error) {
+	if len(req.Options) == 0 {
+		return nil, nil
+	}
+	return req.Options[0], nil
+}
+
+func (p *FirstOptionProvider) ChooseIncidentTarget(req *IncidentTargetRequest) (models.TargetType, error) {
+	if len(req.Options) == 0 {
+		return nil, nil
+	}
+	return req.Options[0], nil
+}
+
+func (p *FirstOptionProvider) RefuseGoodwill(req *GoodwillRefusalRequest) (bool, error) {
+	return false, nil
+}
+
+func (p *FirstOptionProvider) PlaceProtagonistCard(req *PlaceCardsRequest) (CardChoice, error) {
+	for _, card := range req.Hand {
+		for _, target := range req.Targets {
+			if req.CanPlace(card, target) == nil {
+				return CardChoice{Card: card, Target: target}, nil
+			}
+		}
+	}
+	return CardChoice{}, nil
+}
+
+func (p *FirstOptionProvider) ChooseGoodwillAbility(req *GoodwillAbilityRequest) (*GoodwillOption, error) {
+	return nil, nil
+}
+
+func (p *FirstOptionProvider) MakeFinalGuess(req *FinalGuessRequest) (map[models.CharacterName]models.RoleType, error) {
+	guess := make(map[models.CharacterName]models.RoleType, len(req.Characters))
+	for _, name := range req.Characters {
+		guess[name] = models.RolePersonType
+	}
+	return guess, nil
+}
+
+func (p *FirstOptionProvider) DiscussTimeSpiral(req *TimeSpiralRequest) ([]string, error) {
+	ready := make([]string, 0, len(req.View.Protagonists))
+	for _, protagonist := range req.View.Protagonists {
+		ready = append(ready, protagonist.ID)
+	}
+	return ready, nil
+}
@@ -4,41 +4,130 @@ import (
 	"errors"
 	"fmt"
 	"go.uber.org/zap"
+	"slices"
+	"time"
 	"tragedy-looper/engine/internal/models"
+	"tragedy-looper/engine/internal/validate"
 )
 
 type GameController struct {
 	logging *zap.Logger
 	state   *models.GameState
 	script  *models.Script
+
+	mastermindDecider  MastermindDecider  // 幕后主使的决策者
+	protagonistDecider ProtagonistDecider // 主角方的决策者
+	protagonistPlayers int                // 主角方的实际玩家人数
+
+	steps   []engineStep     // 待执行的状态机步骤
+	pending *PendingDecision // 等待回答的决策
+
+	now           func() time.Time   // 当前时间，用于时间螺旋计时
+	spectatorView models.ViewOptions // 观战者可见的信息
 }
 
 func NewGameController(logger *zap.Logger, script *models.Script) *GameController {
+	provider := NewFirstOptionProvider()
 	return &GameController{
-		state:   models.NewGameState(logger),
-		script:  script,
-		logging: logger,
+		state:              models.NewGameState(logger),
+		script:             script,
+		logging:            logger,
+		mastermindDecider:  provider,
+		protagonistDecider: provider,
+		protagonistPlayers: 3,
+		now:                time.Now,
+		spectatorView:      models.SpectatorViewOptions(),
 	}
 }
 
-func (gc *GameController) StartGame() error {
-	gc.logging.Debug("Game initialization started in controller")
+// SetDecisionProvider 设置同时为双方做决策的决策者
+func (gc *GameController) SetDecisionProvider(provider DecisionProvider) {
+	gc.mastermindDecider = provider
+	gc.protagonistDecider = provider
+}
 
-	// 1. 先设置脚本
-	err := gc.setupGame()
-	if err != nil {
-		gc.logging.Error("Game setup failed", zap.Error(err))
-		return err
+// SetMastermindDecider 设置幕后主使的决策者
+func (gc *GameController) SetMastermindDecider(decider MastermindDecider) {
+	gc.mastermindDecider = decider
+}
+
+// SetProtagonistDecider 设置主角方的决策者
+func (gc *GameController) SetProtagonistDecider(decider ProtagonistDecider) {
+	gc.protagonistDecider = decider
+}
+
+// SetProtagonistPlayers 设置主角方的实际玩家人数，少于三人时一名玩家控制多副牌组
+func (gc *GameController) SetProtagonistPlayers(count int) error {
+	if count < 1 || count > 3 {
+		return fmt.Errorf("protagonist players must be between 1 and 3, got %d", count)
 	}
+	gc.protagonistPlayers = count
+	return nil
+}
 
-	gc.logging.Debug("Enter the main game loop from the controller")
+// SetTimeSpiral 设置时间螺旋的讨论时长，为 0 时不限时，等待所有主角准备完毕
+func (gc *GameController) SetTimeSpiral(duration time.Duration) error {
+	if duration < 0 {
+		return fmt.Errorf("time spiral duration must not be negative, got %s", duration)
+	}
+	gc.state.TimeSpiral.Duration = duration
+	return nil
+}
 
-	// 2. 进入游戏主循环
-	err = gc.gameLoop()
-	if err != nil {
-		gc.logging.Error("Game loop failed", zap.Error(err))
+// SetSpectatorView 设置观战者可见的信息
+func (gc *GameController) SetSpectatorView(opts models.ViewOptions) {
+	opts.Seat = models.SeatSpectator
+	gc.spectatorView = opts
+}
+
+// View 返回指定席位可见的游戏状态
+func (gc *GameController) View(seat models.Seat) *models.GameView {
+	switch seat {
+	case models.SeatMastermind:
+		return gc.state.View(models.MastermindViewOptions())
+	case models.SeatProtagonist:
+		return gc.state.View(models.ProtagonistViewOptions())
+	default:
+		return gc.state.View(gc.spectatorView)
+	}
+}
+
+// viewFor 返回玩家所在席位的视图
+func (gc *GameController) viewFor(player models.Player) *models.GameView {
+	return gc.View(models.SeatOf(player))
+}
+
+// StartGame 运行整局游戏直到结束，所有决策交给已配置的决策者
+func (gc *GameController) StartGame() error {
+	if err := gc.Start(); err != nil {
 		return err
 	}
+
+	for {
+		pending, err := gc.Advance()
+		if err != nil {
+			gc.logging.Error("Game loop failed", zap.Error(err))
+			return err
+		}
+		if pending == nil {
+			break
+		}
+
+		answer, err := gc.askProvider(pending)
+		if err != nil {
+			gc.logging.Error("Decision provider failed",
+				zap.String("decision", string(pending.Kind)),
+				zap.Error(err))
+			return err
+		}
+		if err = gc.Answer(answer); err != nil {
+			return err
+		}
+	}
+
+	gc.logging.Debug("Game loop ended",
+		zap.String("Winner", gc.state.WinnerType),
+		zap.Bool("GameOver", gc.state.IsGameOver))
 	return nil
 }
 
@@ -52,9 +141,15 @@ func (gc *GameController) setupGame() error {
 		gc.logging.Error("Setup failed: script already set in state")
 		return errors.New("script is not nil")
 	}
+	if err := validate.ValidateScript(gc.script); err != nil {
+		gc.logging.Error("Setup failed: invalid script", zap.Error(err))
+		return err
+	}
 
 	// 设置脚本到 state
+	gc.state.CurrentGamePhase = models.PhaseScriptSelection
 	gc.state.Script = gc.script
+	gc.state.CurrentGamePhase = models.PhaseCharacterSetup
 
 	gc.logging.Debug("Setup MastermindCLI")
 	gc.state.Mastermind = models.NewMastermind()
@@ -65,6 +160,10 @@ func (gc *GameController) setupGame() error {
 		models.NewProtagonist("B", false),
 		models.NewProtagonist("C", false),
 	}
+	if err := gc.state.Protagonists.AssignPlayers(gc.protagonistPlayers); err != nil {
+		gc.logging.Error("Setup failed: cannot assign protagonist decks", zap.Error(err))
+		return err
+	}
 
 	gc.logging.Debug("Setup script",
 		zap.String("MainPlot", gc.script.MainPlot.Name),
@@ -85,116 +184,119 @@ func (gc *GameController) setupGame() error {
 	}
 
 	gc.logging.Debug("Initialize the game board")
-	gc.state.Board = models.NewBoard(gc.logging, gc.state.Characters)
+	if gc.script.Topology != nil {
+		gc.state.Board = models.NewBoardWithTopology(gc.logging, gc.script.Topology, gc.state.Characters)
+	} else {
+		gc.state.Board = models.NewBoard(gc.logging, gc.state.Characters)
+	}
+	gc.state.BindEvents()
 
 	gc.logging.Debug("Game setup complete")
 	return nil
 }
 
-// gameLoop 游戏主循环
-func (gc *GameController) gameLoop() error {
-	for {
-		// 检查是否达到循环上限
-		if gc.state.CurrentLoop >= gc.script.MaxLoops {
-			gc.logging.Debug("Reached the maximum number of loops, entering final guess phase",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop),
-				zap.Int("MaxLoops", gc.script.MaxLoops))
-			err := gc.enterFinalGuess()
-			if err != nil {
-				gc.logging.Error("Final guess phase failed", zap.Error(err))
-				return err
-			}
-			break
-		}
+// startGameLoop 进入循环阶段
+func (gc *GameController) startGameLoop() error {
+	gc.state.CurrentGamePhase = models.PhaseLoop
+	gc.pushFront(engineStep{name: "NextLoop", run: gc.nextLoop})
+	return nil
+}
 
-		// 准备新的循环
-		err := gc.prepareLoop()
-		if err != nil {
-			gc.logging.Error("Prepare loop failed",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop),
-				zap.Error(err))
-			return err
-		}
+// nextLoop 开始新的循环，达到循环上限时进入最终猜测
+func (gc *GameController) nextLoop() error {
+	if gc.state.CurrentLoop >= gc.script.MaxLoops {
+		gc.logging.Debug("Reached the maximum number of loops, entering final guess phase",
+			zap.Int("CurrentLoop", gc.state.CurrentLoop),
+			zap.Int("MaxLoops", gc.script.MaxLoops))
+		gc.pushFront(engineStep{name: "FinalGuess", run: gc.enterFinalGuess})
+		return nil
+	}
 
-		// 时间螺旋阶段
-		gc.logging.Debug("Enter time spiral phase",
-			zap.Int("CurrentLoop", gc.state.CurrentLoop))
-		err = gc.timeSpiralPhase()
-		if err != nil {
-			gc.logging.Error("Time spiral phase failed",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop),
-				zap.Error(err))
-			return err
-		}
+	gc.pushFront(
+		engineStep{name: "PrepareLoop", run: gc.prepareLoop},
+		gc.loopPhaseStep(models.PhaseTimeSpiral, gc.timeSpiralPhase),
+		gc.loopPhaseStep(models.PhaseCharacterReset, gc.resetCharacters),
+		gc.loopPhaseStep(models.PhaseCountersReset, gc.resetCounters),
+		gc.loopPhaseStep(models.PhaseReturnCards, gc.returnCards),
+		gc.loopPhaseStep(models.PhaseLoopStart, gc.startLoop),
+		gc.loopPhaseStep(models.PhaseDay, gc.nextDay),
+		gc.loopPhaseStep(models.PhaseLoopEnd, gc.endLoop),
+	)
+	return nil
+}
 
-		// 角色归位
-		gc.logging.Debug("Reset character positions",
-			zap.Int("CurrentLoop", gc.state.CurrentLoop))
-		err = gc.state.Board.Reset() // 原来的 reset() 在 Board 上
-		if err != nil {
-			gc.logging.Error("Reset character positions failed",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop),
-				zap.Error(err))
-			return err
-		}
+// loopPhaseStep 创建一个循环阶段步骤，执行前更新当前循环阶段
+func (gc *GameController) loopPhaseStep(phase models.LoopPhase, run func() error) engineStep {
+	return engineStep{
+		name: string(phase),
+		run: func() error {
+			gc.state.CurrentLoopPhase = phase
+			return run()
+		},
+	}
+}
 
-		// 重置计数器
-		gc.logging.Debug("Reset counters", zap.Int("CurrentLoop", gc.state.CurrentLoop))
-		err = gc.state.Board.ResetCounters()
-		if err != nil {
-			gc.logging.Error("Reset counters failed",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop),
-				zap.Error(err))
-			return err
-		}
+// startLoop 循环开始：准备完成后发动循环开始时的能力，再判定循环开始时的剧情规则
+func (gc *GameController) startLoop() error {
+	gc.logging.Debug("Loop started", zap.Int("Loop", gc.state.CurrentLoop))
+	gc.pushFront(engineStep{
+		name: "LoopStartRules",
+		run:  func() error { return gc.evaluatePlotRules(models.RuleTimingLoopStart) },
+	})
+	return gc.triggerAbilities(models.RoleTimingLoopStart)
+}
 
-		// 返回所有卡牌
-		//gc.logging.Debug("Return all cards", zap.Int("CurrentLoop", gc.state.CurrentLoop))
-		//err = gc.state.Board.ReturnAllCards(gc.state)
-		//if err != nil {
-		//	gc.logging.Error("Return cards failed",
-		//		zap.Int("CurrentLoop", gc.state.CurrentLoop),
-		//		zap.Error(err))
-		//	return err
-		//}
+// endLoop 循环结束，发动循环结束时的能力并判定剧情规则后决定胜负
+func (gc *GameController) endLoop() error {
+	gc.pushFront(
+		engineStep{name: "LoopEndRules", run: func() error { return gc.evaluatePlotRules(models.RuleTimingLoopEnd) }},
+		engineStep{name: "LoopResult", run: gc.resolveLoop},
+	)
+	return gc.triggerAbilities(models.RoleTimingLoopEnd)
+}
 
-		// 每日流程
-		gc.logging.Debug("Start daily phase", zap.Int("CurrentLoop", gc.state.CurrentLoop))
-		err = gc.dailyPhases()
-		if err != nil {
-			gc.logging.Error("Daily phase failed",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop),
-				zap.Error(err))
-			return err
-		}
+// resolveLoop 主角方未失败则获胜，否则进入下一循环或最终猜测
+func (gc *GameController) resolveLoop() error {
+	won := gc.checkWinCondition()
+	gc.state.Events.Publish(&models.LoopEnded{
+		ProtagonistsLost: !won,
+		Losses:           gc.state.LoopLosses,
+		EarlyEnd:         gc.state.LoopEnd,
+	})
+	if won {
+		gc.logging.Debug("Protagonists have met the win condition",
+			zap.Int("CurrentLoop", gc.state.CurrentLoop))
+		gc.state.IsGameOver = true
+		gc.state.WinnerType = "Protagonists"
+		gc.state.CurrentGamePhase = models.PhaseGameEnd
+		return nil
+	}
 
-		// 检查胜利条件
-		if gc.checkWinCondition() {
-			gc.logging.Debug("Protagonists have met the win condition",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop))
-			gc.state.IsGameOver = true
-			gc.state.WinnerType = "Protagonists"
-			break
-		} else if gc.state.CurrentLoop >= gc.script.MaxLoops {
-			gc.logging.Debug("Reached the maximum number of loops, entering final guess",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop))
-			err = gc.enterFinalGuess()
-			if err != nil {
-				gc.logging.Error("Final guess phase failed", zap.Error(err))
-				return err
-			}
-			break
-		} else {
-			gc.logging.Debug("Enter the next loop",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop),
-				zap.Int("NextLoop", gc.state.CurrentLoop+1))
-			gc.state.CurrentLoop++
-		}
+	fields := []zap.Field{
+		zap.Int("CurrentLoop", gc.state.CurrentLoop),
+		zap.Int("NextLoop", gc.state.CurrentLoop+1),
 	}
+	if end := gc.state.LoopEnd; end != nil {
+		fields = append(fields,
+			zap.String("cause", string(end.Cause)),
+			zap.String("source", end.Source),
+			zap.Int("day", end.Day))
+	}
+	gc.logging.Debug("Protagonists lost the loop, enter the next loop", fields...)
+	gc.pushFront(engineStep{name: "NextLoop", run: gc.nextLoop})
+	return nil
+}
 
-	gc.logging.Debug("Game loop ended",
-		zap.String("Winner", gc.state.WinnerType),
-		zap.Bool("GameOver", gc.state.IsGameOver))
+// evaluatePlotRules 判定主剧情和子剧情中在该时机生效的失败规则
+func (gc *GameController) evaluatePlotRules(timing models.RuleTiming) error {
+	for _, loss := range gc.state.EvaluateRules(timing) {
+		gc.logging.Info("Plot failure rule met",
+			zap.String("plot", loss.Plot.Name),
+			zap.String("timing", string(timing)),
+			zap.String("reason", loss.Reason),
+			zap.Int("loop", loss.Loop),
+			zap.Int("day", loss.Day))
+	}
 	return nil
 }
 
@@ -206,21 +308,49 @@ func (gc *GameController) enterFinalGuess() error {
 		return errors.New("最终猜测已经进行过")
 	}
 
+	gc.state.CurrentGamePhase = models.PhaseFinalGuess
 	gc.logging.Debug("Protagonists are making the final guess")
-	correctGuess, err := gc.state.Protagonists.MakeFinalGuess(gc.script)
+	request := &FinalGuessRequest{
+		View:       gc.View(models.SeatProtagonist),
+		Characters: gc.characterNames(),
+		Roles:      gc.scriptRoleTypes(),
+	}
+	gc.ask(DecisionFinalGuess, gc.state.Protagonists.GetLeader(), request, func(answer any) error {
+		guess, ok := answer.(map[models.CharacterName]models.RoleType)
+		if !ok {
+			return fmt.Errorf("%w: final guess must be a character to role mapping", ErrInvalidAnswer)
+		}
+		return gc.resolveFinalGuess(guess)
+	})
+	return nil
+}
+
+// resolveFinalGuess 记录并结算主角方的最终猜测
+func (gc *GameController) resolveFinalGuess(guess map[models.CharacterName]models.RoleType) error {
+	if gc.state.GuessMade {
+		gc.logging.Error("Final guess has already been made")
+		return errors.New("最终猜测已经进行过")
+	}
+	outcome, err := gc.state.Protagonists.MakeFinalGuess(gc.state, guess)
 	if err != nil {
-		gc.logging.Error("An error occurred during the final guess", zap.Error(err))
-		return err
+		gc.logging.Debug("Final guess rejected", zap.Error(err))
+		return fmt.Errorf("%w: %v", ErrInvalidAnswer, err)
 	}
 
+	gc.state.FinalGuess = guess
+	gc.state.GuessResult = outcome
 	gc.state.GuessMade = true
 	gc.state.IsGameOver = true
+	gc.state.CurrentGamePhase = models.PhaseGameEnd
 
-	if correctGuess {
-		gc.logging.Debug("Protagonists win because the guess is correct")
+	if outcome.Win {
+		gc.logging.Debug("Protagonists win because the guess is correct",
+			zap.Int("correct", outcome.Correct))
 		gc.state.WinnerType = "Protagonists"
 	} else {
-		gc.logging.Debug("MastermindCLI wins because the final guess is wrong")
+		gc.logging.Debug("MastermindCLI wins because the final guess is wrong",
+			zap.Int("correct", outcome.Correct),
+			zap.Int("total", len(outcome.Results)))
 		gc.state.WinnerType = "MastermindCLI"
 	}
 
@@ -232,99 +362,132 @@ func (gc *GameController) prepareLoop() error {
 	gc.logging.Debug("=================== Preparing New Loop ===================",
 		zap.Int("Loop", gc.state.CurrentLoop+1))
 
+	// 重置游戏状态
+	gc.state.IsGameOver = false
+	gc.state.WinnerType = ""
+	gc.state.CurrentLoop++
+	gc.state.CurrentDay = 0
+	gc.state.CurrentLoopPhase = ""
+	gc.state.CurrentDayPhase = ""
+	gc.state.LoopLosses = nil
+	gc.state.LoopEnd = nil
+	gc.state.UsedRoleAbilities = nil
+	return nil
+}
+
+// timeSpiralPhase 时间螺旋阶段：循环之间主角方讨论并在共享笔记板上留言，
+// 讨论时间用完或所有主角准备完毕后才继续，期间不能放置卡牌
+func (gc *GameController) timeSpiralPhase() error {
 	// 来源: 知识库中的 "Preparing the Loop" 部分
-	gc.logging.Debug("Time Spiral Phase - Protagonists discussion time")
-	// TODO: 实现时间螺旋阶段的具体逻辑
+	if gc.state.CurrentLoop <= 1 {
+		return nil
+	}
+
+	spiral := gc.state.TimeSpiral
+	spiral.Open(gc.state.CurrentLoop, gc.now())
+	gc.logging.Debug("Time Spiral Phase - Protagonists discussion time",
+		zap.Int("Loop", gc.state.CurrentLoop),
+		zap.Duration("Duration", spiral.Duration))
+	gc.askTimeSpiral()
+	return nil
+}
 
+// askTimeSpiral 等待主角准备完毕，任意主角都可以回答
+func (gc *GameController) askTimeSpiral() {
+	spiral := gc.state.TimeSpiral
+	request := &TimeSpiralRequest{
+		View:       gc.View(models.SeatProtagonist),
+		TimeSpiral: spiral,
+		Deadline:   spiral.Deadline(),
+	}
+	gc.ask(DecisionTimeSpiral, gc.state.Protagonists.GetLeader(), request, func(answer any) error {
+		ready, ok := answer.([]string)
+		if !ok {
+			return fmt.Errorf("%w: expected ready protagonist IDs", ErrInvalidAnswer)
+		}
+		if err := spiral.MarkReady(gc.state.Protagonists, ready...); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAnswer, err)
+		}
+		if spiral.AllReady(gc.state.Protagonists) || spiral.Expired(gc.now()) {
+			gc.closeTimeSpiral()
+			return nil
+		}
+		gc.askTimeSpiral()
+		return nil
+	})
+}
+
+// checkTimeSpiral 讨论时间用完时关闭窗口，引擎推进和执行命令前都会检查
+func (gc *GameController) checkTimeSpiral() {
+	if gc.pending == nil || gc.pending.Kind != DecisionTimeSpiral {
+		return
+	}
+	if gc.state.TimeSpiral.Expired(gc.now()) {
+		gc.logging.Debug("Time Spiral discussion time is up")
+		gc.pending = nil
+		gc.closeTimeSpiral()
+	}
+}
+
+// closeTimeSpiral 结束时间螺旋讨论
+func (gc *GameController) closeTimeSpiral() {
+	gc.state.TimeSpiral.Close()
+	gc.logging.Debug("Time Spiral closed",
+		zap.Int("Loop", gc.state.CurrentLoop),
+		zap.Int("Notes", len(gc.state.TimeSpiral.Notes)))
+}
+
+// resetCharacters 角色归位
+func (gc *GameController) resetCharacters() error {
 	gc.logging.Debug("Returning characters to starting positions")
-	err := gc.state.Board.Reset()
-	if err != nil {
+	for _, character := range gc.state.Characters {
+		character.RemoveTraits(gc.state)
+		character.ResetState()
+	}
+	gc.state.Deaths = nil
+	if err := gc.state.Board.Reset(); err != nil {
 		return err
 	}
 
-	gc.logging.Debug("Removing and replacing counters")
-	err = gc.state.Board.ResetCounters()
-	if err != nil {
-		return err
+	// 特征在每个循环开始时重新生效
+	for _, character := range gc.state.Characters {
+		character.ApplyTraits(gc.state)
 	}
+	return nil
+}
 
+// resetCounters 移除和替换计数器
+func (gc *GameController) resetCounters() error {
+	gc.logging.Debug("Removing and replacing counters")
+	return gc.state.Board.ResetCounters()
+}
+
+// returnCards 玩家取回卡牌
+func (gc *GameController) returnCards() error {
 	gc.logging.Debug("Returning all action cards to hands")
-	err = gc.state.Board.ReturnAllCards(gc.state)
+	err := gc.state.Board.ReturnAllCards(gc.state)
 	if err != nil {
 		return err
 	}
 
-	// 重置游戏状态
-	gc.state.IsGameOver = false
-	gc.state.WinnerType = ""
-	gc.state.CurrentLoop++
-	gc.state.CurrentDay = 1
-
-	// 重置角色状态
-	for _, character := range gc.state.Characters {
-		character.ResetState()
-	}
-
 	gc.logging.Debug("Loop preparation completed",
 		zap.Int("NewLoop", gc.state.CurrentLoop))
 
 	// 打印初始状态
 	gc.state.PrintGameState()
-
 	return nil
 }
 
-// timeSpiralPhase 时间螺旋阶段
-func (gc *GameController) timeSpiralPhase() error {
-	// TODO: 实现时间螺旋的逻辑, 例如主角讨论策略
-	return nil
-}
-
-// dailyPhases 每日流程开始
-func (gc *GameController) dailyPhases() error {
-	gc.logging.Debug("Start daily phase",
-		zap.Int("CurrentLoop", gc.state.CurrentLoop),
-		zap.Int("CurrentDay", gc.state.CurrentDay),
-		zap.Int("MaxDays", gc.script.DaysPerLoop))
-
-	for {
-		// 是否为最后一天
-		if gc.state.CurrentDay > gc.script.DaysPerLoop {
-			gc.logging.Debug("Reached the last day of the loop",
-				zap.Int("CurrentLoop", gc.state.CurrentLoop),
-				zap.Int("CompletedDays", gc.state.CurrentDay-1))
-			break
-		}
-		err := gc.processDay()
-		if err != nil {
-			gc.logging.Error("Processing today's routine failed",
-				zap.Int("Day", gc.state.CurrentDay),
-				zap.Int("CurrentLoop", gc.state.CurrentLoop),
-				zap.Error(err))
-			return err
-		}
-		if gc.state.IsGameOver {
-			gc.logging.Debug("Game ended during the daily phase",
-				zap.String("Winner", gc.state.WinnerType),
-				zap.Int("EndDay", gc.state.CurrentDay),
-				zap.Int("EndLoop", gc.state.CurrentLoop))
-			return nil
-		}
-
-		gc.state.CurrentDay++
-		gc.logging.Debug("Enter the next day",
-			zap.Int("NextDay", gc.state.CurrentDay),
-			zap.Int("CurrentLoop", gc.state.CurrentLoop))
+// nextDay 开始新的一天，最后一天结束后交给循环结束阶段
+func (gc *GameController) nextDay() error {
+	if gc.state.CurrentDay >= gc.script.DaysPerLoop {
+		gc.logging.Debug("Reached the last day of the loop",
+			zap.Int("CurrentLoop", gc.state.CurrentLoop),
+			zap.Int("CompletedDays", gc.state.CurrentDay))
+		return nil
 	}
 
-	gc.logging.Debug("Daily phase of the current loop completed",
-		zap.Int("CurrentLoop", gc.state.CurrentLoop),
-		zap.Int("TotalProcessedDays", gc.state.CurrentDay-1))
-	return nil
-}
-
-// processDay 执行一天的流程
-func (gc *GameController) processDay() error {
+	gc.state.CurrentDay++
 	gc.logging.Debug("=================== New Day Started ===================",
 		zap.Int("Day", gc.state.CurrentDay),
 		zap.Int("CurrentLoop", gc.state.CurrentLoop))
@@ -342,42 +505,28 @@ func (gc *GameController) processDay() error {
 		models.PhaseDayEnd,
 	}
 
+	steps := make([]engineStep, 0, len(phases)+1)
 	for _, phase := range phases {
-		gc.logging.Debug("----------- Phase Started -----------",
-			zap.String("Phase", string(phase)))
-
-		err := gc.processDayPhase(phase)
-		if err != nil {
-			gc.logging.Error("Day phase failed",
-				zap.String("phase", string(phase)),
-				zap.Error(err))
-			return err
-		}
-
-		// 在每个阶段结束后打印游戏状态
-		gc.state.PrintGameState()
-
-		if gc.state.IsGameOver {
-			gc.logging.Debug("Game ended during day phases",
-				zap.String("Winner", gc.state.WinnerType))
-			return nil
-		}
+		phase := phase
+		steps = append(steps, engineStep{
+			name: string(phase),
+			run:  func() error { return gc.processDayPhase(phase) },
+		})
 	}
-
-	gc.logging.Debug("=================== Day Completed ===================",
-		zap.Int("Day", gc.state.CurrentDay),
-		zap.Int("CurrentLoop", gc.state.CurrentLoop))
-
+	steps = append(steps, engineStep{name: "NextDay", run: gc.nextDay})
+	gc.pushFront(steps...)
 	return nil
 }
 
 // processDayPhase 处理每日各阶段
 func (gc *GameController) processDayPhase(phase models.DayPhase) error {
-	gc.logging.Debug("Starting to process the day phase",
+	gc.logging.Debug("----------- Phase Started -----------",
 		zap.String("phase", string(phase)),
 		zap.Int("day", gc.state.CurrentDay),
 		zap.Int("CurrentLoop", gc.state.CurrentLoop))
 
+	gc.state.CurrentDayPhase = phase
+
 	var err error
 	switch phase {
 	case models.PhaseDayStart:
@@ -399,16 +548,10 @@ func (gc *GameController) processDayPhase(phase models.DayPhase) error {
 	case models.PhaseDayEnd:
 		err = gc.handleDayEnd()
 	default:
-		return fmt.Errorf("unknown game phase: %d", phase)
+		return fmt.Errorf("unknown game phase: %s", phase)
 	}
 	if err != nil {
-		return err
-	}
-
-	// 触发与此阶段相关的角色能力
-	err = gc.triggerPhaseAbilities(phase)
-	if err != nil {
-		gc.logging.Error("Trigger phase abilities failed",
+		gc.logging.Error("Day phase failed",
 			zap.String("phase", string(phase)),
 			zap.Error(err))
 		return err
@@ -425,15 +568,35 @@ func (gc *GameController) handleDayStart() error {
 func (gc *GameController) handleMastermindAction() error {
 	gc.logging.Debug("MastermindCLI正在放置行动卡...")
 
-	// 直接验证主谋操作
-	if err := gc.state.Mastermind.PlaceActionCards(gc.state); err != nil {
+	mastermind := gc.state.Mastermind
+	if err := mastermind.PlaceActionCards(gc.state); err != nil {
 		return err
 	}
 
-	// 验证已放置3张卡牌
-	if placed := len(gc.state.Board.GetMastermindCards()); placed != 3 {
-		return fmt.Errorf("需要精确放置3张卡牌，当前放置了%d张", placed)
+	request := &PlaceCardsRequest{
+		View:    gc.viewFor(mastermind),
+		Player:  mastermind,
+		Hand:    append([]models.Card(nil), mastermind.GetHandCards()...),
+		Targets: gc.cardTargets(),
+		Count:   mastermind.MaxCardsPerDay,
+		state:   gc.state,
 	}
+	gc.ask(DecisionPlaceMastermindCards, mastermind, request, func(answer any) error {
+		choices, ok := answer.([]CardChoice)
+		if !ok && answer != nil {
+			return fmt.Errorf("%w: expected card placements", ErrInvalidAnswer)
+		}
+
+		// 验证放置3张卡牌，整批合法后才放置
+		if placed := gc.placedCards(mastermind) + len(choices); placed != request.Count {
+			return fmt.Errorf("需要精确放置%d张卡牌，当前放置了%d张", request.Count, placed)
+		}
+		placements, err := gc.resolveCardChoices(choices...)
+		if err != nil {
+			return err
+		}
+		return gc.placeCards(mastermind, placements)
+	})
 	return nil
 }
 
@@ -441,84 +604,406 @@ func (gc *GameController) handleMastermindAction() error {
 func (gc *GameController) handleProtagonistsAction() error {
 	gc.logging.Debug("主角团正在放置行动卡...")
 
-	// 为每个主角直接验证操作
-	for _, p := range gc.state.Protagonists {
-		if err := p.PlaceActionCards(gc.state); err != nil {
-			return fmt.Errorf("主角%s操作失败: %v", p.ID, err)
+	// 领袖最先放置，之后按领袖顺序轮流放置
+	steps := make([]engineStep, 0, len(gc.state.Protagonists))
+	for _, p := range gc.state.Protagonists.InLeaderOrder() {
+		p := p
+		steps = append(steps, engineStep{
+			name: fmt.Sprintf("PlaceCard-%s", p.ID),
+			run:  func() error { return gc.askProtagonistCard(p) },
+		})
+	}
+	gc.pushFront(steps...)
+	return nil
+}
+
+// askProtagonistCard 等待一名主角放置行动卡
+func (gc *GameController) askProtagonistCard(p *models.Protagonist) error {
+	if err := p.PlaceActionCards(gc.state); err != nil {
+		return fmt.Errorf("主角%s操作失败: %v", p.ID, err)
+	}
+
+	request := &PlaceCardsRequest{
+		View:    gc.viewFor(p),
+		Player:  p,
+		Hand:    append([]models.Card(nil), p.GetHandCards()...),
+		Targets: gc.cardTargets(),
+		Count:   p.MaxCardsPerDay,
+		state:   gc.state,
+	}
+	gc.ask(DecisionPlaceProtagonistCard, p, request, func(answer any) error {
+		choice, ok := answer.(CardChoice)
+		if !ok && answer != nil {
+			return fmt.Errorf("%w: expected a card placement", ErrInvalidAnswer)
+		}
+		if choice.Card == nil && gc.placedCards(p) != request.Count {
+			return fmt.Errorf("主角%s没有放置卡牌", p.ID)
+		}
+		if choice.Card != nil {
+			placements, err := gc.resolveCardChoices(choice)
+			if err != nil {
+				return err
+			}
+			if err := gc.placeCards(p, placements); err != nil {
+				return fmt.Errorf("主角%s操作失败: %w", p.ID, err)
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+// resolveCardChoices 将决策者按名称选择的目标解析为卡牌放置
+func (gc *GameController) resolveCardChoices(choices ...CardChoice) ([]models.CardPlacement, error) {
+	placements := make([]models.CardPlacement, 0, len(choices))
+	for _, choice := range choices {
+		target := gc.state.ResolveTarget(choice.Target)
+		if target == nil {
+			return nil, fmt.Errorf("%w: unknown card target %q", ErrInvalidAnswer, choice.Target)
+		}
+		placements = append(placements, models.CardPlacement{Card: choice.Card, Target: target})
+	}
+	return placements, nil
+}
+
+// placeCards 将玩家的一批手牌放置到目标上，任何一张不合法时都不放置
+func (gc *GameController) placeCards(player models.Player, placements []models.CardPlacement) error {
+	for _, placement := range placements {
+		if placement.Card == nil || placement.Target == nil {
+			return errors.New("card placement is incomplete")
+		}
+		if !inHand(player, placement.Card) {
+			return &models.PlacementError{Card: placement.Card, Target: placement.Target, Err: models.ErrCardNotInHand}
+		}
+	}
+	if err := gc.state.Board.CheckPlacements(placements); err != nil {
+		return err
+	}
+	for _, placement := range placements {
+		if err := gc.state.Board.SetCard(placement.Target, placement.Card); err != nil {
+			return err
+		}
+		if err := player.PlaceCards(placement.Card); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// inHand 卡牌是否在玩家手中
+func inHand(player models.Player, card models.Card) bool {
+	for _, c := range player.GetHandCards() {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
+
+// placedCards 返回玩家今天已放置的卡牌数量
+func (gc *GameController) placedCards(player models.Player) int {
+	switch p := player.(type) {
+	case *models.Mastermind:
+		return len(gc.state.Board.GetMastermindCards())
+	case *models.Protagonist:
+		return len(gc.state.Board.GetProtagonistCards(p))
+	default:
+		return 0
+	}
+}
+
+// cardTargets 返回所有可以放置行动卡的目标，具体卡牌能否放置由 Board.CheckPlacement 判断
+func (gc *GameController) cardTargets() []models.TargetRef {
+	targets := make([]models.TargetRef, 0)
+	for _, char := range gc.state.Characters {
+		if char.IsAlive() {
+			targets = append(targets, models.RefOf(char))
+		}
+	}
+	for _, locType := range gc.state.Board.Locations() {
+		if loc := gc.state.Board.GetLocation(locType); loc != nil {
+			targets = append(targets, models.RefOf(loc))
+		}
+	}
+	return targets
+}
+
 // handleResolveCards 处理卡牌结算
 func (gc *GameController) handleResolveCards() error {
 	gc.logging.Debug("Starting to resolve cards...")
 	// 来源: 知识库中提到的卡牌结算顺序
 	gc.logging.Debug("Cards will be resolved in order: 1.Forbid Movement, 2.Movement, 3.Other Forbid, 4.Other cards")
-	return gc.state.Board.ResolveActionCards(gc.state)
+	resolution, err := gc.state.Board.ResolveActionCards(gc.state)
+	gc.state.LastResolution = resolution
+	return err
 }
+
 func (gc *GameController) handleMastermindAbilities() error {
 	return gc.triggerAbilities(models.RoleTimingMastermind)
 }
 
+// handleLeaderGoodwill 领袖发动好感度能力，幕后主使按身份规则决定是否拒绝
 func (gc *GameController) handleLeaderGoodwill() error {
-	return gc.triggerAbilities(models.RoleTimingGoodwillUse)
+	leader := gc.state.Protagonists.GetLeader()
+	if leader == nil {
+		gc.logging.Debug("No leader, skipping goodwill phase")
+		return nil
+	}
+
+	options := gc.goodwillOptions()
+	if len(options) == 0 {
+		gc.logging.Debug("No goodwill ability can be used")
+		return nil
+	}
+
+	request := &GoodwillAbilityRequest{
+		View:    gc.View(models.SeatProtagonist),
+		Leader:  leader,
+		Options: options,
+	}
+	gc.ask(DecisionGoodwillAbility, leader, request, func(answer any) error {
+		choice, ok := answer.(*GoodwillOption)
+		if !ok && answer != nil {
+			return fmt.Errorf("%w: expected a goodwill option", ErrInvalidAnswer)
+		}
+		if choice == nil {
+			gc.logging.Debug("Leader chose not to use a goodwill ability")
+			return nil
+		}
+
+		option := findGoodwillOption(options, choice)
+		if option == nil {
+			return fmt.Errorf("%w: goodwill ability cannot be used", ErrInvalidAnswer)
+		}
+		ref := choice.Target
+		if ref == (models.TargetRef{}) && len(option.Targets) == 1 {
+			ref = option.Targets[0]
+		}
+		target := gc.state.ResolveTarget(ref)
+		if target == nil || !containsRef(option.Targets, ref) {
+			return fmt.Errorf("%w: invalid target for goodwill ability %q", ErrInvalidAnswer, option.Ability.Name)
+		}
+		return gc.resolveGoodwillRefusal(gc.state.Character(option.Character), option.Ability, target)
+	})
+	return nil
 }
 
-func (gc *GameController) handleIncidents() error {
-	gc.logging.Debug("Start processing incidents phase")
+// resolveGoodwillRefusal 按身份的拒绝规则处理好感度能力，必要时询问幕后主使
+func (gc *GameController) resolveGoodwillRefusal(char *models.Character, ability *models.CharacterAbilityData, target models.TargetType) error {
+	switch char.RefusesGoodwill(ability) {
+	case models.GoodwillRefusalMandatory:
+		gc.refuseGoodwill(char, ability)
+		return nil
+	case models.GoodwillRefusalOptional:
+		refusal := &GoodwillRefusalRequest{
+			View:      gc.View(models.SeatMastermind),
+			Character: char,
+			Ability:   ability,
+		}
+		gc.ask(DecisionGoodwillRefusal, gc.state.Mastermind, refusal, func(answer any) error {
+			refused, ok := answer.(bool)
+			if !ok && answer != nil {
+				return fmt.Errorf("%w: expected a refusal decision", ErrInvalidAnswer)
+			}
+			if refused {
+				gc.refuseGoodwill(char, ability)
+				return nil
+			}
+			return gc.useGoodwillAbility(char, ability, target)
+		})
+		return nil
+	default:
+		return gc.useGoodwillAbility(char, ability, target)
+	}
+}
 
-	for _, incident := range gc.state.Script.Incidents {
-		gc.logging.Debug("Check incident", zap.String("IncidentType", string(incident.Type())))
-		if gc.canTriggerIncident(incident) {
-			gc.logging.Debug("Trigger incident", zap.String("IncidentType", string(incident.Type())))
-			err := gc.executeIncident(incident)
-			if err != nil {
-				gc.logging.Error("Execute incident failed",
-					zap.String("IncidentType", string(incident.Type())),
-					zap.Error(err))
-				return err
+// refuseGoodwill 拒绝好感度能力，被拒绝的能力本循环视为已使用
+func (gc *GameController) refuseGoodwill(char *models.Character, ability *models.CharacterAbilityData) {
+	char.MarkGoodwillAbilityUsed(ability)
+	gc.logging.Debug("Mastermind refused the goodwill ability",
+		zap.String("Character", string(char.Name)),
+		zap.String("Ability", ability.Name))
+}
+
+// useGoodwillAbility 执行好感度能力效果
+func (gc *GameController) useGoodwillAbility(char *models.Character, ability *models.CharacterAbilityData, target models.TargetType) error {
+	gc.logging.Debug("Goodwill ability used",
+		zap.String("Character", string(char.Name)),
+		zap.String("Ability", ability.Name))
+	if err := char.UseGoodwillAbility(gc.state, ability, target); err != nil {
+		return err
+	}
+	gc.state.Events.Publish(&models.AbilityUsed{Character: char.Name, Ability: ability.Name, Target: models.RefOf(target)})
+	return nil
+}
+
+// goodwillOptions 返回当前满足好感度条件且有合法目标的所有能力
+func (gc *GameController) goodwillOptions() []GoodwillOption {
+	options := make([]GoodwillOption, 0)
+	for _, char := range gc.state.Characters {
+		for _, ability := range char.UsableGoodwillAbilities() {
+			targets := ability.Targets(gc.state, char)
+			if len(targets) == 0 {
+				continue
+			}
+			refs := make([]models.TargetRef, 0, len(targets))
+			for _, target := range targets {
+				refs = append(refs, models.RefOf(target))
 			}
+			options = append(options, GoodwillOption{Character: char.Name, Ability: ability, Targets: refs})
 		}
 	}
+	return options
+}
 
-	gc.logging.Debug("Incidents phase completed")
+// findGoodwillOption 在合法选项中查找领袖选择的能力
+func findGoodwillOption(options []GoodwillOption, choice *GoodwillOption) *GoodwillOption {
+	for i := range options {
+		if options[i].Character == choice.Character && options[i].Ability == choice.Ability {
+			return &options[i]
+		}
+	}
 	return nil
 }
 
-func (gc *GameController) handleSwitchLeader() error {
-	gc.logging.Debug("Begin handling switch leader, TODO: implement switching logic")
+func (gc *GameController) handleIncidents() error {
+	gc.logging.Debug("Start processing incidents phase")
+
+	steps := make([]engineStep, 0)
+	for _, scheduled := range gc.state.Script.Incidents {
+		scheduled := scheduled
+		gc.logging.Debug("Check incident",
+			zap.String("IncidentType", string(scheduled.Type())),
+			zap.Int("day", scheduled.Day),
+			zap.String("culprit", string(scheduled.Culprit)))
+		if !gc.canTriggerIncident(scheduled) {
+			continue
+		}
+		steps = append(steps, engineStep{
+			name: string(scheduled.Type()),
+			run:  func() error { return gc.triggerIncident(scheduled) },
+		})
+	}
+	gc.pushFront(steps...)
 	return nil
 }
 
-func (gc *GameController) handleDayEnd() error {
-	return gc.triggerAbilities(models.RoleTimingDayEnd)
+// triggerIncident 选择目标并执行事件
+func (gc *GameController) triggerIncident(scheduled *models.ScheduledIncident) error {
+	incident := scheduled.Incident
+	gc.logging.Debug("Trigger incident", zap.String("IncidentType", string(incident.Type())))
+
+	ctx := scheduled.Context(gc.state)
+	execute := func() error {
+		err := gc.executeIncident(incident, ctx)
+		if err != nil {
+			gc.logging.Error("Execute incident failed",
+				zap.String("IncidentType", string(incident.Type())),
+				zap.Error(err))
+		}
+		return err
+	}
+
+	if selector, ok := incident.(models.IncidentMultiTargetSelector); ok {
+		return gc.askIncidentTargets(selector, ctx, execute)
+	}
+	selector, ok := incident.(models.IncidentTargetSelector)
+	if !ok {
+		return execute()
+	}
+	options := selector.TargetOptions(gc.state, ctx.Culprit)
+	if len(options) == 0 {
+		return execute()
+	}
+
+	request := &IncidentTargetRequest{
+		View:     gc.View(models.SeatMastermind),
+		Incident: incident,
+		Culprit:  ctx.Culprit,
+		Options:  options,
+	}
+	gc.ask(DecisionIncidentTarget, gc.state.Mastermind, request, func(answer any) error {
+		target, _ := answer.(models.TargetType)
+		if !containsTarget(options, target) {
+			return fmt.Errorf("%w: invalid target for incident %s", ErrInvalidAnswer, incident.Type())
+		}
+		ctx.Target = target
+		return execute()
+	})
+	return nil
 }
 
-// 触发与阶段对应的角色能力
-func (gc *GameController) triggerPhaseAbilities(phase models.DayPhase) error {
-	switch phase {
-	case models.PhaseDayStart:
-		return gc.triggerAbilities(models.RoleTimingDayStart)
-	case models.PhaseMastermindAbilities:
-		return gc.triggerAbilities(models.RoleTimingMastermind)
-	case models.PhaseDayEnd:
-		return gc.triggerAbilities(models.RoleTimingDayEnd)
-	default:
-		// 其他阶段不触发或已有单独处理
+// askIncidentTargets 依次询问多目标事件的每个目标，选满或没有可选目标时执行事件
+func (gc *GameController) askIncidentTargets(selector models.IncidentMultiTargetSelector, ctx *models.IncidentContext, execute func() error) error {
+	options := selector.NextTargetOptions(gc.state, ctx.Culprit, ctx.Targets)
+	if len(ctx.Targets) >= selector.TargetCount() || len(options) == 0 {
+		return execute()
+	}
+
+	request := &IncidentTargetRequest{
+		View:     gc.View(models.SeatMastermind),
+		Incident: selector,
+		Culprit:  ctx.Culprit,
+		Options:  options,
+		Chosen:   slices.Clone(ctx.Targets),
+	}
+	gc.ask(DecisionIncidentTarget, gc.state.Mastermind, request, func(answer any) error {
+		target, _ := answer.(models.TargetType)
+		if !containsTarget(options, target) {
+			return fmt.Errorf("%w: invalid target for incident %s", ErrInvalidAnswer, selector.Type())
+		}
+		chosen := ctx.Targets
+		ctx.Targets = append(slices.Clone(chosen), target)
+		ctx.Target = ctx.Targets[0]
+		if err := gc.askIncidentTargets(selector, ctx, execute); err != nil {
+			// 事件执行失败时撤销本次选择，决策保持待回答
+			ctx.Targets = chosen
+			ctx.Target = nil
+			if len(chosen) > 0 {
+				ctx.Target = chosen[0]
+			}
+			return err
+		}
 		return nil
+	})
+	return nil
+}
+
+// handleSwitchLeader 领袖交给下一位主角
+func (gc *GameController) handleSwitchLeader() error {
+	previous := gc.state.Protagonists.GetLeader()
+	leader := gc.state.Protagonists.NextLeader()
+	if leader == nil {
+		return errors.New("no protagonists to lead")
+	}
+
+	fields := []zap.Field{zap.String("leader", leader.ID), zap.String("controller", leader.Controller)}
+	if previous != nil {
+		fields = append(fields, zap.String("previous", previous.ID))
 	}
+	gc.logging.Debug("Leader switched", fields...)
+	gc.state.Events.Publish(&models.LeaderChanged{Previous: previous, Leader: leader})
+	return nil
+}
+
+// handleDayEnd 发动日落能力后判定日落时生效的剧情规则
+func (gc *GameController) handleDayEnd() error {
+	gc.pushFront(engineStep{
+		name: "DayEndRules",
+		run:  func() error { return gc.evaluatePlotRules(models.RuleTimingDayEnd) },
+	})
+	return gc.triggerAbilities(models.RoleTimingDayEnd)
 }
 
-// 触发某个 Timing 下的所有能力
+// triggerAbilities 将某个 Timing 下满足条件的能力加入状态机，必须发动的能力先于可选能力
 func (gc *GameController) triggerAbilities(timing models.RoleAbilityTiming) error {
 	gc.logging.Debug("Ability trigger phase started",
 		zap.String("Timing", string(timing)))
 
-	var mustAbilities, mandatoryAbilities, optionalAbilities []models.RoleAbility
+	var mandatoryAbilities, optionalAbilities []engineStep
 
 	for _, character := range gc.state.Characters {
-		if !character.IsAlive() {
+		if !character.IsAlive() && !triggersWhenDead(timing) {
 			continue
 		}
 		role := character.Role()
@@ -526,7 +1011,7 @@ func (gc *GameController) triggerAbilities(timing models.RoleAbilityTiming) erro
 			continue
 		}
 		for _, ability := range role.Abilities {
-			if ability.GetTiming() != timing {
+			if ability.GetTiming() != timing || !gc.state.CanUseRoleAbility(character, ability) {
 				continue
 			}
 			isTriggerable, err := ability.IsTriggerable(gc.state, character)
@@ -536,58 +1021,175 @@ func (gc *GameController) triggerAbilities(timing models.RoleAbilityTiming) erro
 			if !isTriggerable {
 				continue
 			}
-			switch ability.GetMandatory() {
-			case models.GoodwillRefusalMust:
-				mustAbilities = append(mustAbilities, ability)
-			case models.GoodwillRefusalOptional:
-				optionalAbilities = append(optionalAbilities, ability)
-			case models.GoodwillRefusalMandatory:
-				mandatoryAbilities = append(mandatoryAbilities, ability)
+
+			triggered := triggeredAbility{character: character, ability: ability}
+			if ability.GetMandatory() == models.AbilityOptional {
+				optionalAbilities = append(optionalAbilities, gc.abilityStep(triggered, true))
+			} else {
+				mandatoryAbilities = append(mandatoryAbilities, gc.abilityStep(triggered, false))
 			}
 		}
 	}
 
-	// 执行 "must" abilities
-	for _, ability := range mustAbilities {
-		err := ability.Execute(gc.state, nil)
-		if err != nil {
-			return err
-		}
+	steps := append(mandatoryAbilities, optionalAbilities...)
+	gc.pushFront(steps...)
+	return nil
+}
+
+// triggeredAbility 已满足触发条件的角色能力
+type triggeredAbility struct {
+	character *models.Character
+	ability   models.RoleAbility
+}
+
+// triggersWhenDead 循环结束时的能力由能力自身判断条件，例如密友死亡时失败，因此死亡角色也参与触发
+func triggersWhenDead(timing models.RoleAbilityTiming) bool {
+	return timing == models.RoleTimingLoopEnd
+}
+
+// abilityStep 创建发动能力的步骤，执行前重新检查角色状态
+func (gc *GameController) abilityStep(triggered triggeredAbility, optional bool) engineStep {
+	return engineStep{
+		name: fmt.Sprintf("Ability-%s-%s", triggered.character.Name, triggered.ability.RoleType()),
+		run: func() error {
+			if !triggered.character.IsAlive() && !triggersWhenDead(triggered.ability.GetTiming()) {
+				return nil
+			}
+			return gc.executeAbility(triggered, optional)
+		},
 	}
+}
 
-	// 执行 "mandatory" abilities
-	for _, ability := range mandatoryAbilities {
-		err := ability.Execute(gc.state, nil)
-		if err != nil {
-			return err
-		}
+// executeAbility 选择目标并发动能力，可选能力由幕后主使决定是否发动
+func (gc *GameController) executeAbility(triggered triggeredAbility, optional bool) error {
+	options := []models.TargetType{triggered.character}
+	if targeted, ok := triggered.ability.(models.TargetedRoleAbility); ok {
+		options = targeted.TargetOptions(gc.state, triggered.character)
+	}
+	if len(options) == 0 {
+		gc.logging.Debug("Ability has no legal target",
+			zap.String("Character", string(triggered.character.Name)),
+			zap.String("Role", string(triggered.ability.RoleType())))
+		return nil
 	}
 
-	// 执行 "optional" abilities
-	for _, ability := range optionalAbilities {
-		err := ability.Execute(gc.state, nil)
-		if err != nil {
-			return err
+	if !optional && len(options) == 1 {
+		return gc.useRoleAbility(triggered.character, triggered.ability, options[0])
+	}
+
+	request := &AbilityTargetRequest{
+		View:      gc.View(models.SeatMastermind),
+		Character: triggered.character,
+		Ability:   triggered.ability,
+		Options:   options,
+		Optional:  optional,
+	}
+	gc.ask(DecisionAbilityTarget, gc.state.Mastermind, request, func(answer any) error {
+		chosen, _ := answer.(models.TargetType)
+		if chosen == nil {
+			if optional {
+				gc.logging.Debug("Mastermind chose not to use the ability",
+					zap.String("Character", string(triggered.character.Name)),
+					zap.String("Role", string(triggered.ability.RoleType())))
+				return nil
+			}
+			return fmt.Errorf("%w: ability of %s requires a target", ErrInvalidAnswer, triggered.character.Name)
+		}
+		if !containsTarget(options, chosen) {
+			return fmt.Errorf("%w: invalid target for ability of %s", ErrInvalidAnswer, triggered.character.Name)
 		}
+		return gc.useRoleAbility(triggered.character, triggered.ability, chosen)
+	})
+	return nil
+}
+
+// useRoleAbility 发动角色的身份能力并发布能力使用事件
+func (gc *GameController) useRoleAbility(character *models.Character, ability models.RoleAbility, target models.RoleAbilityTarget) error {
+	if err := ability.Execute(gc.state, target); err != nil {
+		return err
 	}
+	gc.state.MarkRoleAbilityUsed(character, ability)
+	used := &models.AbilityUsed{Character: character.Name, Role: ability.RoleType()}
+	if t, ok := target.(models.TargetType); ok {
+		used.Target = models.RefOf(t)
+	}
+	gc.state.Events.Publish(used)
+	return nil
+}
+
+// canTriggerIncident 判断事件今天是否触发：日期相符，当事人存活且不安值达到上限
+func (gc *GameController) canTriggerIncident(scheduled *models.ScheduledIncident) bool {
+	if !scheduled.CanOccur(gc.state) {
+		return false
+	}
+	return scheduled.Incident.IsTriggerable(*gc.logging, gc.state, scheduled.Context(gc.state))
+}
 
+// executeIncident 执行事件并记录发生的循环与日期
+func (gc *GameController) executeIncident(incident models.Incident, ctx *models.IncidentContext) error {
+	if err := incident.Execute(*gc.logging, gc.state, ctx); err != nil {
+		return err
+	}
+	gc.state.RecordIncident(ctx.Loop, ctx.Day, incident.Type())
+	occurred := &models.IncidentOccurred{Incident: incident.Type()}
+	if ctx.Culprit != nil {
+		occurred.Culprit = ctx.Culprit.Name
+	}
+	gc.state.Events.Publish(occurred)
+	gc.logging.Info("Incident occurred",
+		zap.String("IncidentType", string(incident.Type())),
+		zap.Int("loop", ctx.Loop),
+		zap.Int("day", ctx.Day))
 	return nil
 }
 
-// canTriggerIncident 判断事件是否可以触发
-func (gc *GameController) canTriggerIncident(incident models.Incident) bool {
-	// 可以根据实际需求改成更精细的判断
-	return true
+// characterNames 返回剧本中所有角色的名称
+func (gc *GameController) characterNames() []models.CharacterName {
+	names := make([]models.CharacterName, 0, len(gc.state.Characters))
+	for _, char := range gc.state.Characters {
+		names = append(names, char.Name)
+	}
+	return names
+}
+
+// scriptRoleTypes 返回剧本所属惨剧集中的所有身份
+//
+// 身份列表来自公开的惨剧集而不是剧本的剧情，避免向主角方泄露隐藏的剧情。
+func (gc *GameController) scriptRoleTypes() []models.RoleType {
+	set, ok := models.LookupTragedySet(gc.script.TragedySet)
+	if !ok {
+		gc.logging.Warn("Unknown tragedy set, only Person can be guessed",
+			zap.String("TragedySet", gc.script.TragedySet))
+		return []models.RoleType{models.RolePersonType}
+	}
+	roles := []models.RoleType{models.RolePersonType}
+	for _, roleType := range set.Roles() {
+		if roleType != models.RolePersonType {
+			roles = append(roles, roleType)
+		}
+	}
+	return roles
+}
+
+func containsTarget(options []models.TargetType, target models.TargetType) bool {
+	for _, option := range options {
+		if option == target {
+			return true
+		}
+	}
+	return false
 }
 
-// executeIncident 执行事件
-func (gc *GameController) executeIncident(incident models.Incident) error {
-	// 调用事件的 Execute 方法
-	return incident.Execute(*gc.logging, gc.state, nil)
+func containsRef(options []models.TargetRef, ref models.TargetRef) bool {
+	for _, option := range options {
+		if option == ref {
+			return true
+		}
+	}
+	return false
 }
 
-// checkWinCondition 检查胜利条件
+// checkWinCondition 检查胜利条件：主角方在本循环没有失败即获胜
 func (gc *GameController) checkWinCondition() bool {
-	// 根据游戏规则判断主角方是否获胜
-	return false
+	return !gc.state.ProtagonistsLostLoop()
 }
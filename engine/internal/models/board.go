@@ -8,82 +8,71 @@ import (
 
 // Board 代表游戏的主要状态
 type Board struct {
-	logging          *zap.Logger // 添加日志记录器
-	characters       []*Character
-	locations        map[LocationType]*Location // 所有位置的映射表
-	forbiddenActions map[any]CardType           // 被禁止的动作
-	actionCards      []Card                     // 在此位置上打出的行动卡
+	logging     *zap.Logger // 添加日志记录器
+	topology    *Topology   // 地图结构
+	characters  []*Character
+	locations   map[LocationType]*Location // 所有位置的映射表
+	actionCards []Card                     // 在此位置上打出的行动卡
+	events      *EventBus                  // 领域事件总线
 }
 
-// NewBoard 初始化一个新的游戏板
+// NewBoard 使用标准地图初始化一个新的游戏板
 func NewBoard(logging *zap.Logger, characters []*Character) *Board {
+	return NewBoardWithTopology(logging, StandardTopology(), characters)
+}
+
+// NewBoardWithTopology 使用指定地图初始化一个新的游戏板
+func NewBoardWithTopology(logging *zap.Logger, topology *Topology, characters []*Character) *Board {
 	board := &Board{
 		logging:    logging,
+		topology:   topology,
 		characters: characters,
 		locations:  nil,
 	}
 	return board
 }
 
-// Reset 初始化游戏板上的所有位置及其方向关系
-func (board *Board) Reset() error {
-	board.locations = make(map[LocationType]*Location)
-
-	// 初始化所有位置
-	hospital := &Location{
-		LocationType: LocationHospital,
-		Characters:   make(map[CharacterName]*Character),
-	}
-	city := &Location{
-		LocationType: LocationCity,
-		Characters:   make(map[CharacterName]*Character),
+// SetEventBus 设置游戏板及其位置发布事件的总线
+func (board *Board) SetEventBus(bus *EventBus) {
+	board.events = bus
+	for _, loc := range board.locations {
+		loc.events = bus
 	}
-	school := &Location{
-		LocationType: LocationSchool,
-		Characters:   make(map[CharacterName]*Character),
-	}
-	shrine := &Location{
-		LocationType: LocationShrine,
-		Characters:   make(map[CharacterName]*Character),
-	}
-
-	// 记录位置初始化
-	board.logging.Debug("All locations have been initialized")
-
-	// 设置地图位置关系
-	hospital.right = shrine
-	hospital.bottom = city
-	hospital.diagonal = school
-
-	city.right = school
-	city.top = hospital
-	city.diagonal = shrine
-
-	school.top = shrine
-	school.left = city
-	school.diagonal = hospital
-
-	shrine.right = hospital
-	shrine.bottom = school
-	shrine.diagonal = city
+}
 
-	board.logging.Debug("The location relationships have been set")
+// Topology 返回游戏板使用的地图
+func (board *Board) Topology() *Topology {
+	return board.topology
+}
 
-	// 将位置加入到board的locations映射中
-	board.locations[LocationHospital] = hospital
-	board.locations[LocationCity] = city
-	board.locations[LocationSchool] = school
-	board.locations[LocationShrine] = shrine
+// Reset 按地图初始化游戏板上的所有位置，并将角色放回起始位置
+func (board *Board) Reset() error {
+	if err := board.topology.Validate(); err != nil {
+		board.logging.Error("Invalid board topology", zap.Error(err))
+		return err
+	}
 
-	board.logging.Debug("The locations were added to the game board mapping",
-		zap.Reflect("hospital", hospital),
-		zap.Reflect("city", city),
-		zap.Reflect("school", school),
-		zap.Reflect("shrine", shrine))
+	board.locations = make(map[LocationType]*Location)
+	for _, locType := range board.topology.LocationTypes() {
+		board.locations[locType] = &Location{
+			LocationType: locType,
+			Attributes:   make(Attributes),
+			Characters:   make(map[CharacterName]*Character),
+			events:       board.events,
+		}
+	}
+	board.logging.Debug("All locations have been initialized",
+		zap.String("topology", board.topology.Name),
+		zap.Int("locationCount", len(board.locations)))
 
 	// 将角色放置在起始位置
 	for _, char := range board.characters {
 		loc := board.locations[char.StartLocation]
+		if loc == nil {
+			err := fmt.Errorf("%w: %s starts at %s", ErrUnknownLocation, char.Name, char.StartLocation)
+			board.logging.Error("Character start location is not on the board", zap.Error(err))
+			return err
+		}
 		loc.Characters[char.Name] = char
 		board.logging.Debug("The character has been placed at the starting location",
 			zap.String("character", string(char.Name)),
@@ -95,21 +84,26 @@ func (board *Board) Reset() error {
 }
 
 // IsAdjacent 检查两个位置是否相邻
-func (l *Location) IsAdjacent(other *Location) bool {
-	return l.left == other ||
-		l.right == other ||
-		l.top == other ||
-		l.bottom == other ||
-		l.diagonal == other
+func (board *Board) IsAdjacent(a, b LocationType) bool {
+	return board.topology.IsAdjacent(a, b)
 }
 
-// ResolveActionCards 按照规则顺序处理所有行动卡
-func (board *Board) ResolveActionCards(gs *GameState) error {
-	board.logging.Debug("Starting to process action cards")
+// Destination 返回从某位置按方向移动后到达的位置
+func (board *Board) Destination(from LocationType, direction MovementDirection) (*Location, error) {
+	to, err := board.topology.Destination(from, direction)
+	if err != nil {
+		return nil, err
+	}
+	location := board.locations[to]
+	if location == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLocation, to)
+	}
+	return location, nil
+}
 
-	// 重置禁止动作
-	board.forbiddenActions = make(map[any]CardType)
-	board.logging.Debug("Forbidden actions have been Reset")
+// ResolveActionCards 按照规则顺序处理所有行动卡，返回每张卡牌的结算记录
+func (board *Board) ResolveActionCards(gs *GameState) (*Resolution, error) {
+	board.logging.Debug("Starting to process action cards")
 
 	// 收集所有行动卡
 	allCards := board.collectAllActionCards()
@@ -118,17 +112,42 @@ func (board *Board) ResolveActionCards(gs *GameState) error {
 	// 揭示所有卡牌
 	for _, card := range allCards {
 		card.Reveal()
+		board.events.Publish(&CardRevealed{Card: fullCardView(card)})
 	}
 	board.logging.Debug("All cards have been revealed")
 
 	// 按优先级排序
-	sort.Slice(allCards, func(i, j int) bool {
+	sort.SliceStable(allCards, func(i, j int) bool {
 		return allCards[i].Priority() < allCards[j].Priority()
 	})
 	board.logging.Debug("All cards have been sorted by priority")
 
+	// 计算禁止卡的效果
+	resolution := resolveForbids(gs, allCards)
+
+	// 所有移动卡合并为每个角色的一次净移动
+	board.resolveMovement(resolution)
+
 	// 处理卡牌
-	for _, card := range allCards {
+	for _, result := range resolution.Results {
+		card := result.Card
+		if result.Negated {
+			board.logging.Debug("Card has been negated",
+				zap.String("cardID", card.Id()),
+				zap.String("reason", string(result.Reason)),
+				zap.Int("negatedBy", len(result.NegatedBy)))
+			board.events.Publish(&CardNegated{Card: fullCardView(card), Reason: result.Reason, NegatedBy: fullCardViews(result.NegatedBy)})
+			continue
+		}
+		if _, ok := card.(*MovementCard); ok {
+			continue
+		}
+		if result.IgnoredBy != nil {
+			board.logging.Debug("Forbid card ignored by role ability",
+				zap.String("cardID", card.Id()),
+				zap.String("character", string(result.IgnoredBy.Name)))
+		}
+
 		board.logging.Debug("Processing card",
 			zap.String("cardID", card.Id()),
 			zap.String("cardType", string(card.Type())))
@@ -137,91 +156,42 @@ func (board *Board) ResolveActionCards(gs *GameState) error {
 			board.logging.Error("Failed to process card",
 				zap.String("cardID", card.Id()),
 				zap.Error(err))
-			return fmt.Errorf("failed to process card: %w", err)
+			return resolution, fmt.Errorf("failed to process card: %w", err)
 		}
 	}
 
-	// 处理后续效果
-	err := board.handleCardsAfterResolution(allCards)
-	if err != nil {
-		board.logging.Error("Failed to process subsequent card effects", zap.Error(err))
-		return err
-	}
-
-	err = board.ReturnAllCards(gs)
+	// 结算完成后所有卡牌返回手牌
+	err := board.ReturnAllCards(gs)
 	if err != nil {
 		board.logging.Error("Failed to return all cards", zap.Error(err))
-		return err
+		return resolution, err
 	}
 
-	board.logging.Debug("All action cards have been successfully processed")
-	return nil
+	board.logging.Debug("All action cards have been successfully processed",
+		zap.Int("negatedCount", len(resolution.Negated())))
+	return resolution, nil
 }
 
-// applyCardEffect 应用卡牌效果
+// applyCardEffect 应用卡牌效果，禁止卡和移动卡已分别在 resolveForbids、resolveMovement 中处理
 func (board *Board) applyCardEffect(card Card) error {
 	board.logging.Debug("Applying card effect",
 		zap.String("cardID", card.Id()),
 		zap.String("cardType", string(card.Type())))
 
 	switch c := card.(type) {
-	case *MovementCard:
-		return board.handleMovementCard(c)
-	case *ForbidMovementCard:
-		board.forbiddenActions[card.Target()] = ForbidMovementType
-		board.logging.Debug("The forbidden movement effect has been applied",
-			zap.Any("target", card.Target()))
-		return nil
 	case *IntrigueCard:
 		return board.handleIntrigueCard(c)
-	case *ForbidIntrigueCard:
-		board.forbiddenActions[card.Target()] = ForbidIntrigueType
-		board.logging.Debug("The forbidden intrigue effect has been applied",
-			zap.Any("target", card.Target()))
-		return nil
 	case *GoodwillCard:
 		return board.handleGoodwillCard(c)
-	case *ForbidGoodwillCard:
-		board.forbiddenActions[card.Target()] = ForbidGoodwillType
-		board.logging.Debug("The forbidden goodwill effect has been applied",
-			zap.Any("target", card.Target()))
-		return nil
 	case *ParanoiaCard:
 		return board.handleParanoiaCard(c)
-	case *ForbidParanoiaCard:
-		board.forbiddenActions[card.Target()] = ForbidParanoiaType
+	case *MovementCard, *ForbidMovementCard, *ForbidIntrigueCard, *ForbidGoodwillCard, *ForbidParanoiaCard:
+		return nil
 	default:
 		err := fmt.Errorf("unknown card type")
 		board.logging.Error("Unknown card type", zap.Error(err))
 		return err
 	}
-	return nil
-}
-
-// handleMovementCard 处理移动卡牌
-func (board *Board) handleMovementCard(movementCard *MovementCard) error {
-	board.logging.Debug("Processing movement card",
-		zap.Any("target", movementCard.Target()),
-		zap.String("direction", string(movementCard.Direction)))
-
-	if _, ok := board.forbiddenActions[movementCard.Target()]; ok {
-		board.logging.Debug("Target is prohibited from moving",
-			zap.Any("target", movementCard.Target()))
-		return nil
-	}
-
-	target := movementCard.Target()
-	if target == nil {
-		err := fmt.Errorf("the movement card's target is nil")
-		board.logging.Error("Invalid movement target", zap.Error(err))
-		return err
-	}
-
-	target.ToLocation(board, movementCard.Direction)
-	board.logging.Debug("Target has been successfully moved",
-		zap.Any("target", target),
-		zap.String("direction", string(movementCard.Direction)))
-	return nil
 }
 
 // handleIntrigueCard 处理阴谋卡牌
@@ -230,12 +200,6 @@ func (board *Board) handleIntrigueCard(intrigueCard *IntrigueCard) error {
 		zap.Any("target", intrigueCard.Target()),
 		zap.Int("value", intrigueCard.Value))
 
-	if _, ok := board.forbiddenActions[intrigueCard.Target()]; ok {
-		board.logging.Debug("Target is prohibited from intrigue action",
-			zap.Any("target", intrigueCard.Target()))
-		return nil
-	}
-
 	target := intrigueCard.Target()
 	if target == nil {
 		err := fmt.Errorf("intrigue card target is nil")
@@ -258,12 +222,6 @@ func (board *Board) handleGoodwillCard(goodwillCard *GoodwillCard) error {
 		zap.Any("target", goodwillCard.Target()),
 		zap.Int("value", goodwillCard.Value))
 
-	if _, ok := board.forbiddenActions[goodwillCard.Target()]; ok {
-		board.logging.Debug("Target is prohibited from goodwill action",
-			zap.Any("target", goodwillCard.Target()))
-		return nil
-	}
-
 	target := goodwillCard.Target()
 	if target == nil {
 		err := fmt.Errorf("invalid goodwill target")
@@ -283,11 +241,6 @@ func (board *Board) handleParanoiaCard(c *ParanoiaCard) error {
 	board.logging.Debug("Processing paranoia card",
 		zap.Any("target", c.Target()),
 		zap.Int("value", c.Value))
-	if _, ok := board.forbiddenActions[c.Target()]; ok {
-		board.logging.Debug("Target is prohibited from paranoia action",
-			zap.Any("target", c.Target()))
-		return nil
-	}
 	target := c.Target()
 	if target == nil {
 		err := fmt.Errorf("invalid paranoia target")
@@ -310,38 +263,17 @@ func (board *Board) collectAllActionCards() []Card {
 	return board.actionCards
 }
 
-// handleCardsAfterResolution 处理卡牌结算后的效果
-func (board *Board) handleCardsAfterResolution(cards []Card) error {
-	board.logging.Debug("Processing card effects after resolution",
-		zap.Int("cardCount", len(cards)))
-
-	for _, card := range cards {
-		board.logging.Debug("Returning the card to the hand",
-			zap.String("cardID", card.Id()),
-			zap.String("cardType", string(card.Type())))
-
-		err := ReturnToHand(card)
-		if err != nil {
-			board.logging.Error("Failed to return the card to the hand",
-				zap.String("cardID", card.Id()),
-				zap.Error(err))
-			return err
-		}
-	}
-
-	board.logging.Debug("All card effects after resolution have been successfully processed")
-	return nil
-}
-
-// SetCard 在指定位置添加卡牌
+// SetCard 在指定位置添加卡牌，违反放置规则时返回 *PlacementError
 func (board *Board) SetCard(target TargetType, card Card) error {
+	if card == nil {
+		return &PlacementError{Target: target, Err: ErrInvalidPlacement}
+	}
 	board.logging.Debug("Adding card to target",
 		zap.String("cardID", card.Id()),
 		zap.Any("target", target))
 
-	if !card.IsValidTarget(target) {
-		err := fmt.Errorf("invalid card target")
-		board.logging.Error("Invalid card target",
+	if err := board.CheckPlacement(target, card); err != nil {
+		board.logging.Debug("Card placement rejected",
 			zap.String("cardID", card.Id()),
 			zap.Error(err))
 		return err
@@ -356,6 +288,7 @@ func (board *Board) SetCard(target TargetType, card Card) error {
 	}
 
 	board.actionCards = append(board.actionCards, card)
+	board.events.Publish(&CardPlaced{Card: fullCardView(card)})
 
 	board.logging.Debug("Card has been successfully added to target")
 	return nil
@@ -376,18 +309,16 @@ func (board *Board) MoveTo(character *Character, location LocationType) error {
 		zap.String("character", string(character.Name)),
 		zap.String("targetLocation", string(location)))
 
-	if !character.CanMoveTo(location) {
-		err := fmt.Errorf("character cannot move to this location")
-		board.logging.Error("Character cannot move to this location",
+	to := board.locations[location]
+	if to == nil {
+		err := fmt.Errorf("%w: %s", ErrUnknownLocation, location)
+		board.logging.Error("Target location is not on the board",
 			zap.String("character", string(character.Name)),
-			zap.String("targetLocation", string(location)),
 			zap.Error(err))
 		return err
 	}
 
-	delete(board.locations, character.CurrentLocation)
-	board.locations[location].Characters[character.Name] = character
-
+	from := character.CurrentLocation
 	err := character.MoveTo(location)
 	if err != nil {
 		board.logging.Error("Failed to move character",
@@ -396,14 +327,24 @@ func (board *Board) MoveTo(character *Character, location LocationType) error {
 			zap.Error(err))
 		return err
 	}
+	board.relocate(character, from, to)
+	board.events.Publish(&CharacterMoved{Character: character.Name, From: from, To: location})
 
 	board.logging.Debug("Character has been successfully moved",
 		zap.String("character", string(character.Name)),
-		zap.String("from", string(character.CurrentLocation)),
+		zap.String("from", string(from)),
 		zap.String("to", string(location)))
 	return nil
 }
 
+// relocate 将角色从原位置的角色表移到新位置的角色表
+func (board *Board) relocate(character *Character, from LocationType, to *Location) {
+	if loc := board.locations[from]; loc != nil {
+		delete(loc.Characters, character.Name)
+	}
+	to.Characters[character.Name] = character
+}
+
 // ResetCounters 重置所有计数器
 func (board *Board) ResetCounters() error {
 	board.logging.Debug("Starting to Reset all counters")
@@ -490,12 +431,6 @@ func (board *Board) ReturnAllCards(state *GameState) error {
 	board.logging.Debug("The action cards on the board have been cleared",
 		zap.Int("clearedCardCount", cardCount))
 
-	// 清空禁止动作
-	forbiddenCount := len(board.forbiddenActions)
-	board.forbiddenActions = make(map[any]CardType)
-	board.logging.Debug("Forbidden actions have been Reset",
-		zap.Int("clearedActionCount", forbiddenCount))
-
 	// 处理玩家已使用的卡牌
 	for _, protagonist := range state.Protagonists {
 		usedCards := protagonist.OnceCards
@@ -530,13 +465,9 @@ func (board *Board) ReturnAllCards(state *GameState) error {
 	return nil
 }
 
+// Locations 按地图顺序返回所有位置类型
 func (board *Board) Locations() []LocationType {
-	return []LocationType{
-		"LocationHospital",
-		"LocationCity",
-		"LocationSchool",
-		"LocationShrine",
-	}
+	return board.topology.LocationTypes()
 }
 
 func (board *Board) GetMastermindCards() (cards []Card) {
@@ -1,6 +1,9 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // CardType 表示卡牌类型的枚举
 type CardType string
@@ -139,7 +142,7 @@ type MovementCard struct {
 func NewMovementCard(owner Player, direction MovementDirection, oncePerLoop bool) *MovementCard {
 	return &MovementCard{
 		BaseCard: NewBaseCard(BaseCardData{
-			id:          "move",
+			id:          fmt.Sprintf("move_%s", strings.ToLower(string(direction))),
 			cardType:    MovementType,
 			priority:    2,
 			oncePerLoop: oncePerLoop,
@@ -154,9 +157,9 @@ func (c *MovementCard) SetTarget(target TargetType) error {
 	return nil
 }
 
-// IsValidTarget 检查目标是否有效
+// IsValidTarget 移动卡只能放置在角色上
 func (c *MovementCard) IsValidTarget(target TargetType) bool {
-	return true
+	return isCharacterTarget(target)
 }
 
 // IntrigueCard 情报卡实现
@@ -205,8 +208,9 @@ func NewParanoiaCard(owner Player, value int, oncePerLoop bool) *ParanoiaCard {
 	}
 }
 
+// IsValidTarget 不安卡只能放置在角色上
 func (p *ParanoiaCard) IsValidTarget(target TargetType) bool {
-	return true
+	return isCharacterTarget(target)
 }
 
 func (p *ParanoiaCard) SetTarget(target TargetType) error {
@@ -233,9 +237,9 @@ func NewGoodwillCard(owner Player, value int, oncePerLoop bool) *GoodwillCard {
 	}
 }
 
-// IsValidTarget 检查目标是否有效
+// IsValidTarget 好感卡只能放置在角色上
 func (c *GoodwillCard) IsValidTarget(target TargetType) bool {
-	return true
+	return isCharacterTarget(target)
 }
 
 // SetTarget 设置好感卡的目标
@@ -261,9 +265,9 @@ func NewForbidMovementCard(owner Player, oncePerLoop bool) *ForbidMovementCard {
 	}
 }
 
-// IsValidTarget 检查目标是否有效
+// IsValidTarget 禁止移动卡只能放置在角色上
 func (c *ForbidMovementCard) IsValidTarget(target TargetType) bool {
-	return true
+	return isCharacterTarget(target)
 }
 
 // SetTarget 设置禁止移动卡的目标
@@ -315,8 +319,10 @@ func NewForbidParanoiaCard(owner Player, oncePerLoop bool) *ForbidParanoiaCard {
 		}, owner),
 	}
 }
+
+// IsValidTarget 禁止不安卡只能放置在角色上
 func (c *ForbidParanoiaCard) IsValidTarget(target TargetType) bool {
-	return true
+	return isCharacterTarget(target)
 }
 
 func (c *ForbidParanoiaCard) SetTarget(target TargetType) error {
@@ -341,9 +347,9 @@ func NewForbidGoodwillCard(owner Player, oncePerLoop bool) *ForbidGoodwillCard {
 	}
 }
 
-// IsValidTarget 检查目标是否有效
+// IsValidTarget 禁止好感卡只能放置在角色上
 func (c *ForbidGoodwillCard) IsValidTarget(target TargetType) bool {
-	return true
+	return isCharacterTarget(target)
 }
 
 // SetTarget 设置禁止好感卡的目标
@@ -357,3 +363,9 @@ func (c *BaseCard) IsPlayable() bool {
 		!c.state.isDiscarded &&
 		c.data.maxUsagePerLoop > c.state.usedCount
 }
+
+// CardPlacement 表示一次行动卡放置
+type CardPlacement struct {
+	Card   Card       // 放置的卡牌
+	Target TargetType // 放置的目标
+}
@@ -9,6 +9,13 @@ type CharacterName string
 type Character struct {
 	*CharacterData
 	*CharacterState
+
+	events *EventBus // 计数器变化发布到的事件总线
+}
+
+// SetEventBus 设置角色发布事件的总线
+func (c *Character) SetEventBus(bus *EventBus) {
+	c.events = bus
 }
 
 func (c *Character) Intrigue() int {
@@ -66,7 +73,11 @@ func (c *Character) SetAttribute(attr AttributeType, value int) {
 			value = 0
 		}
 	}
+	old := c.CharacterState.Attributes.Get(attr)
 	c.CharacterState.Attributes.Set(attr, value)
+	if old != value {
+		c.events.Publish(&CounterChanged{Target: RefOf(c), Attribute: attr, Old: old, New: value})
+	}
 }
 
 func (c *Character) Location() LocationType {
@@ -76,33 +87,19 @@ func (c *Character) Location() LocationType {
 
 func (c *Character) ToLocation(board *Board, movementDirection MovementDirection) {
 	// 根据移动方向移动角色到新位置
-	currentLoc := board.GetLocation(c.CharacterState.CurrentLocation)
-	if currentLoc == nil {
-		return
-	}
-
-	// 获取目标位置
-	nextLoc, err := currentLoc.getNextLocation(movementDirection)
+	nextLoc, err := board.Destination(c.CharacterState.CurrentLocation, movementDirection)
 	if err != nil {
 		return
 	}
-
-	// 从当前位置移除角色
-	delete(currentLoc.Characters, c.Name)
-
-	// 更新角色位置
-	c.CharacterState.CurrentLocation = nextLoc.LocationType
-
-	// 将角色添加到新位置
-	nextLoc.Characters[c.Name] = c
+	_ = board.MoveTo(c, nextLoc.LocationType)
 }
 
 type CharacterTag string
 
 // CharacterData 角色静态数据
 type CharacterData struct {
-	Name                CharacterName // 角色名称
-	Tags                []CharacterTag
+	Name                CharacterName           // 角色名称
+	Tags                []CharacterTag          // 标签
 	StartLocation       LocationType            // 初始位置
 	ForbidMovement      []LocationType          // 禁止移动Id() string
 	Traits              []CharacterTrait        // 特征
@@ -128,10 +125,13 @@ type CharacterState struct {
 	IsMoved            bool           // 本日是否已移动
 	ForbiddenLocations []LocationType // 禁止移动的位置
 	Role               *Role          // 当前角色身份
+
+	UsedGoodwillAbilities map[*CharacterAbilityData]bool // 本循环已使用的好感度能力
 }
 
 // CharacterTrait 角色特征
 type CharacterTrait struct {
+	Type    TraitType
 	Effects []TraitEffect
 }
 
@@ -143,10 +143,23 @@ type TraitEffect interface {
 
 // CharacterAbilityData 能力数据
 type CharacterAbilityData struct {
-	Name         string                 // 能力
-	Cost         int                    // 好感度
-	CanBeRefused bool                   // 是否可被拒绝
-	Effect       func(*GameState) error // 能力效果
+	Name         string // 能力
+	Cost         int    // 好感度
+	CanBeRefused bool   // 是否可被拒绝
+	OncePerLoop  bool   // 每个循环只能使用一次
+
+	// TargetOptions 返回能力的可选目标，为空时以角色自身为目标
+	TargetOptions func(state *GameState, source *Character) []TargetType
+	// Effect 能力效果
+	Effect func(state *GameState, target TargetType) error
+}
+
+// Targets 返回能力当前的可选目标
+func (a *CharacterAbilityData) Targets(state *GameState, source *Character) []TargetType {
+	if a.TargetOptions == nil {
+		return []TargetType{source}
+	}
+	return a.TargetOptions(state, source)
 }
 
 // NewCharacter 创建新角色
@@ -184,6 +197,12 @@ func (c *Character) CanMoveTo(location LocationType) bool {
 			return false
 		}
 	}
+	// 检查游戏中附加的禁止位置
+	for _, forbidden := range c.ForbiddenLocations {
+		if forbidden == location {
+			return false
+		}
+	}
 	return true
 }
 
@@ -220,7 +239,9 @@ type TraitType string
 // HasTrait 检查是否具有指定特征
 func (c *Character) HasTrait(traitType TraitType) bool {
 	for _, trait := range c.Traits {
-		_ = trait.Effects
+		if trait.Type == traitType {
+			return true
+		}
 	}
 	return false
 }
@@ -250,7 +271,7 @@ func (c *Character) IsAlive() bool {
 	return c.CharacterState.IsAlive
 }
 
-// Kill 使角色死亡
+// Kill 使角色死亡，只修改状态；游戏中的死亡应通过 GameState.KillCharacter
 func (c *Character) Kill() error {
 	if !c.CharacterState.IsAlive {
 		return fmt.Errorf("角色已死")
@@ -290,25 +311,56 @@ func (c *Character) GetGoodwillAbility() []*CharacterAbilityData {
 	return c.GoodwillAbilityList
 }
 
-// CanUseGoodwillAbility 检查是否可以使用好感度能力
+// CanUseGoodwillAbility 检查是否有可以使用的好感度能力
 func (c *Character) CanUseGoodwillAbility() bool {
-	//return c.GoodwillAbilityList != nil &&
-	//c.HasSufficientGoodwill(c.GoodwillAbilityList.Cost)
-	return false
+	return len(c.UsableGoodwillAbilities()) > 0
 }
 
-// UseGoodwillAbility 使用好感度能力
-func (c *Character) UseGoodwillAbility(gs *GameState, ability RoleAbility) error {
-	if !c.CanUseGoodwillAbility() {
-		return fmt.Errorf("无法使用好感度能力")
+// CanUseAbility 检查指定好感度能力是否满足好感度条件且本循环未用尽
+func (c *Character) CanUseAbility(ability *CharacterAbilityData) bool {
+	if !c.IsAlive() || !c.HasSufficientGoodwill(ability.Cost) {
+		return false
 	}
+	return !ability.OncePerLoop || !c.UsedGoodwillAbilities[ability]
+}
 
-	//if c.GoodwillAbilityList.CanBeRefused && ability.GetMandatory() == GoodwillRefusalMandatory {
-	//	return fmt.Errorf("角色拒绝了能力")
-	//}
-	//
-	//c.GoodwillAbilityList.Effect(gs)
-	return nil
+// UsableGoodwillAbilities 返回当前可以使用的好感度能力
+func (c *Character) UsableGoodwillAbilities() []*CharacterAbilityData {
+	abilities := make([]*CharacterAbilityData, 0)
+	for _, ability := range c.GoodwillAbilityList {
+		if c.CanUseAbility(ability) {
+			abilities = append(abilities, ability)
+		}
+	}
+	return abilities
+}
+
+// MarkGoodwillAbilityUsed 记录好感度能力本循环已使用（被拒绝也视为已使用）
+func (c *Character) MarkGoodwillAbilityUsed(ability *CharacterAbilityData) {
+	if c.UsedGoodwillAbilities == nil {
+		c.UsedGoodwillAbilities = make(map[*CharacterAbilityData]bool)
+	}
+	c.UsedGoodwillAbilities[ability] = true
+}
+
+// RefusesGoodwill 返回角色身份对该好感度能力的拒绝规则
+func (c *Character) RefusesGoodwill(ability *CharacterAbilityData) GoodwillRefusal {
+	if !ability.CanBeRefused || c.Role() == nil {
+		return GoodwillRefusalNone
+	}
+	return c.Role().GoodwillRefusalRule()
+}
+
+// UseGoodwillAbility 使用好感度能力并执行其效果
+func (c *Character) UseGoodwillAbility(gs *GameState, ability *CharacterAbilityData, target TargetType) error {
+	if !c.CanUseAbility(ability) {
+		return fmt.Errorf("无法使用好感度能力: %s", ability.Name)
+	}
+	c.MarkGoodwillAbilityUsed(ability)
+	if ability.Effect == nil {
+		return nil
+	}
+	return ability.Effect(gs, target)
 }
 
 // ResetState 重置角色状态(新循环开始时)
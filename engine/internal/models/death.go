@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ErrAlreadyDead 角色已经死亡
+var ErrAlreadyDead = errors.New("character is already dead")
+
+// DeathCause 角色死亡的原因
+type DeathCause string
+
+const (
+	DeathByIncident DeathCause = "Incident" // 事件导致的死亡
+	DeathByAbility  DeathCause = "Ability"  // 身份能力导致的死亡
+	DeathByEffect   DeathCause = "Effect"   // 其他效果导致的死亡，如指令
+)
+
+// Death 一次角色死亡的记录，尸体留在死亡时的位置
+type Death struct {
+	Character *Character
+	Cause     DeathCause
+	Killer    string       // 导致死亡的实体，如角色名或事件类型
+	Location  LocationType // 死亡时所在的位置
+	Loop      int
+	Day       int
+	Prevented bool       // 是否被阻止
+	SavedBy   *Character // 阻止死亡的角色
+}
+
+// DeathPreventionAbility 可以阻止角色死亡的身份能力
+type DeathPreventionAbility interface {
+	RoleAbility
+	// PreventsDeath 能力所属角色是否阻止这次死亡
+	PreventsDeath(gameState *GameState, source *Character, death *Death) bool
+}
+
+// KillCharacter 角色死亡的唯一流程：检查死亡阻止效果，记录死亡原因与凶手，
+// 并将死亡加入待处理队列，由引擎发动死亡时能力。被阻止时返回的记录 Prevented 为 true。
+func (gs *GameState) KillCharacter(character *Character, cause DeathCause, killer string) (*Death, error) {
+	if !character.IsAlive() {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyDead, character.Name)
+	}
+
+	death := &Death{
+		Character: character,
+		Cause:     cause,
+		Killer:    killer,
+		Location:  character.Location(),
+		Loop:      gs.CurrentLoop,
+		Day:       gs.CurrentDay,
+	}
+	if saver := gs.deathPrevention(death); saver != nil {
+		death.Prevented = true
+		death.SavedBy = saver
+		gs.logging.Debug("Death prevented",
+			zap.String("character", string(character.Name)),
+			zap.String("savedBy", string(saver.Name)))
+		return death, nil
+	}
+
+	if err := character.Kill(); err != nil {
+		return nil, err
+	}
+	gs.Deaths = append(gs.Deaths, death)
+	gs.pendingDeaths = append(gs.pendingDeaths, death)
+	gs.Events.Publish(&CharacterDied{
+		Character: character.Name,
+		Cause:     cause,
+		Killer:    killer,
+		Location:  death.Location,
+	})
+	gs.logging.Debug("Character died",
+		zap.String("character", string(character.Name)),
+		zap.String("cause", string(cause)),
+		zap.String("killer", killer),
+		zap.String("location", string(death.Location)))
+	return death, nil
+}
+
+// TakePendingDeaths 取出尚未发动死亡时能力的死亡记录
+func (gs *GameState) TakePendingDeaths() []*Death {
+	deaths := gs.pendingDeaths
+	gs.pendingDeaths = nil
+	return deaths
+}
+
+// deathPrevention 返回可以阻止这次死亡的角色
+func (gs *GameState) deathPrevention(death *Death) *Character {
+	for _, char := range gs.Characters {
+		if !char.IsAlive() || char.Role() == nil {
+			continue
+		}
+		for _, ability := range char.Role().Abilities {
+			prevention, ok := ability.(DeathPreventionAbility)
+			if ok && prevention.PreventsDeath(gs, char, death) {
+				return char
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+// guardAbility 阻止指定角色因指定原因死亡
+type guardAbility struct {
+	passiveAbility
+	ward  CharacterName
+	cause DeathCause
+}
+
+func (a *guardAbility) PreventsDeath(gameState *GameState, source *Character, death *Death) bool {
+	return death.Character.Name == a.ward && death.Cause == a.cause
+}
+
+// setGuard 让 guard 角色保护 ward 角色免于 cause 导致的死亡
+func (f *boardFixture) setGuard(guard, ward CharacterName, cause DeathCause) {
+	f.character(guard).SetRole(&Role{Type: "TestRole", Abilities: []RoleAbility{&guardAbility{ward: ward, cause: cause}}})
+}
+
+func TestKillCharacter(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(f *boardFixture)
+		cause DeathCause
+		err   error
+		// savedBy 阻止 Boy 死亡的角色，为空时 Boy 死亡
+		savedBy CharacterName
+	}{
+		{name: "character dies", cause: DeathByIncident},
+		{
+			name:    "another character prevents the death",
+			setup:   func(f *boardFixture) { f.setGuard("Doctor", "Boy", DeathByIncident) },
+			cause:   DeathByIncident,
+			savedBy: "Doctor",
+		},
+		{
+			name:    "character prevents its own death",
+			setup:   func(f *boardFixture) { f.setGuard("Boy", "Boy", DeathByAbility) },
+			cause:   DeathByAbility,
+			savedBy: "Boy",
+		},
+		{
+			name:  "prevention only covers its cause",
+			setup: func(f *boardFixture) { f.setGuard("Doctor", "Boy", DeathByIncident) },
+			cause: DeathByAbility,
+		},
+		{
+			name:  "prevention only covers its ward",
+			setup: func(f *boardFixture) { f.setGuard("Doctor", "Girl", DeathByIncident) },
+			cause: DeathByIncident,
+		},
+		{
+			name: "dead guard cannot prevent",
+			setup: func(f *boardFixture) {
+				f.setGuard("Doctor", "Boy", DeathByIncident)
+				if _, err := f.gs.KillCharacter(f.character("Doctor"), DeathByEffect, "test"); err != nil {
+					f.t.Fatal(err)
+				}
+				f.gs.TakePendingDeaths()
+			},
+			cause: DeathByIncident,
+		},
+		{
+			name: "character is already dead",
+			setup: func(f *boardFixture) {
+				if _, err := f.gs.KillCharacter(f.character("Boy"), DeathByEffect, "test"); err != nil {
+					f.t.Fatal(err)
+				}
+				f.gs.TakePendingDeaths()
+			},
+			cause: DeathByIncident,
+			err:   ErrAlreadyDead,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBoardFixture(t)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			f.gs.CurrentLoop, f.gs.CurrentDay = 2, 3
+			deaths := len(f.gs.Deaths)
+			boy := f.character("Boy")
+
+			death, err := f.gs.KillCharacter(boy, tt.cause, "Murderer")
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			pending := f.gs.TakePendingDeaths()
+			if err != nil {
+				if len(f.gs.Deaths) != deaths || len(pending) != 0 {
+					t.Error("a failed kill recorded a death")
+				}
+				return
+			}
+
+			if death.Cause != tt.cause || death.Killer != "Murderer" || death.Location != LocationSchool ||
+				death.Loop != 2 || death.Day != 3 {
+				t.Errorf("death = %+v", death)
+			}
+			if tt.savedBy != "" {
+				if !death.Prevented || death.SavedBy == nil || death.SavedBy.Name != tt.savedBy {
+					t.Errorf("death prevented = %v by %v, want by %s", death.Prevented, death.SavedBy, tt.savedBy)
+				}
+				if !boy.IsAlive() || len(f.gs.Deaths) != deaths || len(pending) != 0 {
+					t.Error("a prevented death killed the character")
+				}
+				return
+			}
+
+			if death.Prevented || boy.IsAlive() {
+				t.Fatalf("Boy alive = %v, prevented = %v", boy.IsAlive(), death.Prevented)
+			}
+			if len(f.gs.Deaths) != deaths+1 || len(pending) != 1 || pending[0] != death {
+				t.Errorf("deaths = %d, pending = %d", len(f.gs.Deaths)-deaths, len(pending))
+			}
+			// 尸体留在死亡时的位置
+			if got := boy.Location(); got != LocationSchool {
+				t.Errorf("corpse is at %s, want %s", got, LocationSchool)
+			}
+			if more := f.gs.TakePendingDeaths(); len(more) != 0 {
+				t.Errorf("%d pending deaths left after taking them", len(more))
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package models
+
+import "sync"
+
+// EventType 领域事件类型
+type EventType string
+
+const (
+	EventCounterChanged   EventType = "CounterChanged"   // 计数器变化
+	EventCharacterMoved   EventType = "CharacterMoved"   // 角色移动
+	EventCardPlaced       EventType = "CardPlaced"       // 放置行动卡
+	EventCardRevealed     EventType = "CardRevealed"     // 翻开行动卡
+	EventCardNegated      EventType = "CardNegated"      // 行动卡被无效化
+	EventCharacterDied    EventType = "CharacterDied"    // 角色死亡
+	EventIncidentOccurred EventType = "IncidentOccurred" // 事件发生
+	EventAbilityUsed      EventType = "AbilityUsed"      // 发动能力
+	EventLeaderChanged    EventType = "LeaderChanged"    // 领袖交替
+	EventLoopEnded        EventType = "LoopEnded"        // 循环结束
+)
+
+// Event 游戏中发生的领域事件
+type Event interface {
+	Type() EventType
+	// When 事件发生的循环和日期
+	When() (loop, day int)
+
+	stamp(loop, day int)
+}
+
+// EventTime 事件发生的时间，嵌入到每个事件中，由事件总线发布时填写
+type EventTime struct {
+	Loop int
+	Day  int
+}
+
+func (t *EventTime) When() (loop, day int) {
+	return t.Loop, t.Day
+}
+
+func (t *EventTime) stamp(loop, day int) {
+	t.Loop = loop
+	t.Day = day
+}
+
+// 事件以名称引用角色和位置，订阅者无法通过对象指针读取身份等隐藏信息
+
+// CounterChanged 角色或位置上的计数器发生变化
+type CounterChanged struct {
+	EventTime
+	Target    TargetRef
+	Attribute AttributeType
+	Old       int
+	New       int
+}
+
+// CharacterMoved 角色移动到新位置
+type CharacterMoved struct {
+	EventTime
+	Character CharacterName
+	From      LocationType
+	To        LocationType
+}
+
+// CardPlaced 行动卡被放置到目标上，订阅席位看不到内容时 Card.Hidden 为 true
+type CardPlaced struct {
+	EventTime
+	Card CardView
+}
+
+// CardRevealed 行动卡在结算时被翻开
+type CardRevealed struct {
+	EventTime
+	Card CardView
+}
+
+// CardNegated 行动卡被禁止卡无效化
+type CardNegated struct {
+	EventTime
+	Card      CardView
+	Reason    NegationReason
+	NegatedBy []CardView
+}
+
+// CharacterDied 角色死亡，被阻止的死亡不会发布
+type CharacterDied struct {
+	EventTime
+	Character CharacterName
+	Cause     DeathCause
+	Killer    string // 导致死亡的实体，身份对订阅席位隐藏时为空
+	Location  LocationType
+}
+
+// IncidentOccurred 事件发生
+type IncidentOccurred struct {
+	EventTime
+	Incident IncidentType
+	Culprit  CharacterName // 当事人对订阅席位隐藏时为空
+}
+
+// AbilityUsed 角色发动了身份能力或好感度能力
+type AbilityUsed struct {
+	EventTime
+	Character CharacterName
+	Role      RoleType // 身份能力所属身份，好感度能力或身份对订阅席位隐藏时为空
+	Ability   string   // 好感度能力名称，身份能力为空
+	Target    TargetRef
+}
+
+// LeaderChanged 领袖交给下一位主角
+type LeaderChanged struct {
+	EventTime
+	Previous *Protagonist
+	Leader   *Protagonist
+}
+
+// LoopEnded 循环结束及其结果
+type LoopEnded struct {
+	EventTime
+	ProtagonistsLost bool
+	Losses           []*LoopLoss   // 失败的剧情和规则，剧情对订阅席位隐藏时为空
+	EarlyEnd         *EarlyLoopEnd // 提前结束的记录，正常结束时为空
+}
+
+func (*CounterChanged) Type() EventType   { return EventCounterChanged }
+func (*CharacterMoved) Type() EventType   { return EventCharacterMoved }
+func (*CardPlaced) Type() EventType       { return EventCardPlaced }
+func (*CardRevealed) Type() EventType     { return EventCardRevealed }
+func (*CardNegated) Type() EventType      { return EventCardNegated }
+func (*CharacterDied) Type() EventType    { return EventCharacterDied }
+func (*IncidentOccurred) Type() EventType { return EventIncidentOccurred }
+func (*AbilityUsed) Type() EventType      { return EventAbilityUsed }
+func (*LeaderChanged) Type() EventType    { return EventLeaderChanged }
+func (*LoopEnded) Type() EventType        { return EventLoopEnded }
+
+// EventFilter 订阅时的事件过滤条件，返回 true 时投递
+type EventFilter func(Event) bool
+
+// OfType 只接收指定类型的事件
+func OfType(types ...EventType) EventFilter {
+	return func(event Event) bool {
+		for _, t := range types {
+			if event.Type() == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ForCharacter 只接收与指定角色相关的事件
+func ForCharacter(name CharacterName) EventFilter {
+	return func(event Event) bool {
+		switch e := event.(type) {
+		case *CounterChanged:
+			return e.Target.Character == name
+		case *CharacterMoved:
+			return e.Character == name
+		case *CardPlaced:
+			return e.Card.Target == string(name)
+		case *CharacterDied:
+			return e.Character == name
+		case *IncidentOccurred:
+			return e.Culprit == name
+		case *AbilityUsed:
+			return e.Character == name
+		default:
+			return false
+		}
+	}
+}
+
+// EventHandler 事件处理函数
+type EventHandler func(Event)
+
+type subscription struct {
+	id      int
+	handler EventHandler
+	filters []EventFilter
+}
+
+// EventBus 同步投递领域事件的总线，界面、日志、AI 和持久化都可以订阅
+//
+// nil 总线上的发布会被忽略，未接入总线的角色和游戏板照常工作。
+type EventBus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   []*subscription
+	clock  func() (loop, day int)
+}
+
+// NewEventBus 创建事件总线，clock 为事件提供当前循环和日期，可以为空
+func NewEventBus(clock func() (loop, day int)) *EventBus {
+	return &EventBus{clock: clock}
+}
+
+// Subscribe 订阅事件，满足所有过滤条件的事件才会投递，返回取消订阅的函数
+//
+// 投递的事件包含全部隐藏信息，面向玩家的订阅应使用 SubscribeView。
+func (bus *EventBus) Subscribe(handler EventHandler, filters ...EventFilter) (unsubscribe func()) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.nextID++
+	id := bus.nextID
+	bus.subs = append(bus.subs, &subscription{id: id, handler: handler, filters: filters})
+	return func() { bus.unsubscribe(id) }
+}
+
+// SubscribeView 以席位的可见范围订阅事件，与 GameView 一样隐藏 opts 不允许看到的信息
+//
+// 其他席位面朝下的卡牌内容、身份、凶手、事件当事人和失败的剧情会被隐藏，
+// 投递的是事件的副本，过滤条件也只能看到隐藏后的事件。
+func (bus *EventBus) SubscribeView(opts ViewOptions, handler EventHandler, filters ...EventFilter) (unsubscribe func()) {
+	return bus.Subscribe(func(event Event) {
+		redacted := redactEvent(event, opts)
+		for _, filter := range filters {
+			if !filter(redacted) {
+				return
+			}
+		}
+		handler(redacted)
+	})
+}
+
+// redactEvent 返回 opts 可见的事件，需要隐藏信息时返回副本而不修改原事件
+func redactEvent(event Event, opts ViewOptions) Event {
+	switch e := event.(type) {
+	case *CardPlaced:
+		hidden := *e
+		hidden.Card = redactCard(e.Card, opts)
+		return &hidden
+	case *CardNegated:
+		hidden := *e
+		hidden.Card = redactCard(e.Card, opts)
+		hidden.NegatedBy = make([]CardView, 0, len(e.NegatedBy))
+		for _, card := range e.NegatedBy {
+			hidden.NegatedBy = append(hidden.NegatedBy, redactCard(card, opts))
+		}
+		return &hidden
+	case *AbilityUsed:
+		if opts.ShowRoles {
+			return e
+		}
+		hidden := *e
+		hidden.Role = ""
+		return &hidden
+	case *CharacterDied:
+		// 身份能力导致的死亡以身份持有者为凶手
+		if opts.ShowRoles {
+			return e
+		}
+		hidden := *e
+		hidden.Killer = ""
+		return &hidden
+	case *IncidentOccurred:
+		if opts.ShowCulprits {
+			return e
+		}
+		hidden := *e
+		hidden.Culprit = ""
+		return &hidden
+	case *LoopEnded:
+		if opts.ShowPlots {
+			return e
+		}
+		hidden := *e
+		hidden.Losses = nil
+		return &hidden
+	default:
+		return event
+	}
+}
+
+func (bus *EventBus) unsubscribe(id int) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	for i, sub := range bus.subs {
+		if sub.id == id {
+			bus.subs = append(bus.subs[:i:i], bus.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Publish 填写事件时间后按订阅顺序投递事件
+func (bus *EventBus) Publish(event Event) {
+	if bus == nil {
+		return
+	}
+	if bus.clock != nil {
+		event.stamp(bus.clock())
+	}
+
+	bus.mu.RLock()
+	subs := append([]*subscription(nil), bus.subs...)
+	bus.mu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.accepts(event) {
+			sub.handler(event)
+		}
+	}
+}
+
+func (sub *subscription) accepts(event Event) bool {
+	for _, filter := range sub.filters {
+		if !filter(event) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidGuess 最终猜测不合法
+var ErrInvalidGuess = errors.New("invalid final guess")
+
+// GuessResult 单个角色的猜测结果
+type GuessResult struct {
+	Character CharacterName
+	Guessed   RoleType // 主角方猜测的身份
+	Actual    RoleType // 实际身份
+	Correct   bool
+}
+
+// FinalGuessOutcome 最终猜测的结算结果
+type FinalGuessOutcome struct {
+	Results []*GuessResult // 按角色顺序排列的每个角色结果
+	Correct int            // 猜对的角色数
+	Win     bool           // 全部猜对时主角方获胜
+}
+
+// Result 返回指定角色的猜测结果
+func (o *FinalGuessOutcome) Result(name CharacterName) *GuessResult {
+	for _, result := range o.Results {
+		if result.Character == name {
+			return result
+		}
+	}
+	return nil
+}
+
+// RoleRanges 返回剧本所有剧情加入的身份及数量范围，Person 不计数
+func (s *Script) RoleRanges() map[RoleType]RoleRange {
+	ranges := make(map[RoleType]RoleRange)
+	for _, plot := range s.Plots() {
+		for _, roleType := range plot.Roles {
+			ranges[roleType] = ranges[roleType].Add(plot.RoleRanges[roleType])
+		}
+	}
+	return ranges
+}
+
+// ValidateGuess 检查猜测覆盖剧本的每个角色，且每个身份都属于剧本的惨剧集
+//
+// 猜测只按公开的惨剧集检查，不按剧本的剧情检查，
+// 避免主角方通过被拒绝的猜测试探隐藏的剧情和身份数量。
+func ValidateGuess(gameState *GameState, guess map[CharacterName]RoleType) error {
+	for name := range guess {
+		if gameState.Character(name) == nil {
+			return fmt.Errorf("%w: unknown character %s", ErrInvalidGuess, name)
+		}
+	}
+	set, _ := LookupTragedySet(gameState.Script.TragedySet)
+	for _, char := range gameState.Characters {
+		roleType, ok := guess[char.Name]
+		if !ok {
+			return fmt.Errorf("%w: no role guessed for %s", ErrInvalidGuess, char.Name)
+		}
+		if roleType != RolePersonType && (set == nil || !set.AllowsRole(roleType)) {
+			return fmt.Errorf("%w: role %s is not in tragedy set %q", ErrInvalidGuess, roleType, gameState.Script.TragedySet)
+		}
+	}
+	return nil
+}
@@ -3,21 +3,21 @@ package models
 import (
 	"fmt"
 	"go.uber.org/zap"
-	"time"
 )
 
 // state 管理整个游戏的状态
 
 type GameState struct {
 	logging          *zap.Logger
-	Script           *Script   // 当前剧本
-	CurrentGamePhase GamePhase // 当前游戏主阶段
-	CurrentLoopPhase LoopPhase // 当前循环阶段
-	CurrentDayPhase  DayPhase  // 当前日阶段
-	CurrentLoop      int       // 当前循环
-	CurrentDay       int       // 当前日期
-	CurrentPlayer    Player    // 当前玩家
-	TimeSpiral       time.Time // 时间螺旋阶段（TODO：具体实现时间螺旋的逻辑）
+	Script           *Script     // 当前剧本
+	CurrentGamePhase GamePhase   // 当前游戏主阶段
+	CurrentLoopPhase LoopPhase   // 当前循环阶段
+	CurrentDayPhase  DayPhase    // 当前日阶段
+	CurrentLoop      int         // 当前循环
+	CurrentDay       int         // 当前日期
+	CurrentPlayer    Player      // 当前玩家
+	TimeSpiral       *TimeSpiral // 循环之间的时间螺旋讨论及共享笔记板
+	Events           *EventBus   // 领域事件总线
 
 	IsGameOver   bool         // 游戏是否结束
 	WinnerType   string       // 获胜方类型
@@ -25,41 +25,63 @@ type GameState struct {
 	Protagonists Protagonists // 主人公
 	Mastermind   *Mastermind  // 幕后主使
 
-	GuessMade bool // 是否进行了最终猜测
+	LastResolution *Resolution   // 最近一次卡牌结算的记录
+	LoopLosses     []*LoopLoss   // 主角方本循环失败的原因
+	LoopEnd        *EarlyLoopEnd // 本循环提前结束的记录，未提前结束时为空
+	Deaths         []*Death      // 本循环的死亡记录
+	pendingDeaths  []*Death      // 尚未发动死亡时能力的死亡
 
-	Roles []*Role
+	GuessMade   bool                       // 是否进行了最终猜测
+	FinalGuess  map[CharacterName]RoleType // 主角方的最终猜测
+	GuessResult *FinalGuessOutcome         // 最终猜测的结算结果
 
-	IncidentsOccurred map[string]bool
+	Roles         []*Role
+	RevealedRoles map[CharacterName]bool // 已向所有玩家公开身份的角色
+
+	IncidentsOccurred map[IncidentKey][]IncidentType      // 每个循环每天已发生的事件
 	TimingAbility     map[RoleAbilityTiming][]RoleAbility // 当前阶段可用的角色能力
+	UsedRoleAbilities map[RoleAbilityUse]bool             // 本循环已使用的每循环一次的身份能力
 	Characters        []*Character                        // 游戏中的所有角色
 	RoleTypes         map[RoleType]*Character             // 角色身份对应的角色
 	ActiveRoles       map[string]*RoleAbility             // 当前激活的角色能力
-	Incidents         []Incident
+	Incidents         []*ScheduledIncident
 }
 
 func NewGameState(logging *zap.Logger) *GameState {
-	return &GameState{
-		logging:      logging,
-		Script:       nil,
-		CurrentPhase: PhaseDayStart,
-		CurrentLoop:  0,
-		CurrentDay:   0,
-		TimeSpiral:   time.Time{},
-		IsGameOver:   false,
-		WinnerType:   "",
-		Board:        nil,
-		Protagonists: nil,
-		Mastermind:   nil,
-		GuessMade:    false,
-		Characters:   nil,
-		Incidents:    nil,
-		Roles:        nil,
-
-		IncidentsOccurred: make(map[string]bool),
+	gs := &GameState{
+		logging:          logging,
+		Script:           nil,
+		CurrentGamePhase: PhaseGameStart,
+		CurrentLoop:      0,
+		CurrentDay:       0,
+		TimeSpiral:       NewTimeSpiral(0),
+		IsGameOver:       false,
+		WinnerType:       "",
+		Board:            nil,
+		Protagonists:     nil,
+		Mastermind:       nil,
+		GuessMade:        false,
+		Characters:       nil,
+		Incidents:        nil,
+		Roles:            nil,
+
+		IncidentsOccurred: make(map[IncidentKey][]IncidentType),
 		TimingAbility:     make(map[RoleAbilityTiming][]RoleAbility),
 		RoleTypes:         make(map[RoleType]*Character),
 		ActiveRoles:       make(map[string]*RoleAbility),
 	}
+	gs.Events = NewEventBus(func() (int, int) { return gs.CurrentLoop, gs.CurrentDay })
+	return gs
+}
+
+// BindEvents 将游戏板和所有角色接入事件总线，之后它们的状态变化都会发布事件
+func (gs *GameState) BindEvents() {
+	if gs.Board != nil {
+		gs.Board.SetEventBus(gs.Events)
+	}
+	for _, c := range gs.Characters {
+		c.SetEventBus(gs.Events)
+	}
 }
 func (gs *GameState) Character(characterName CharacterName) *Character {
 	for _, c := range gs.Characters {
@@ -74,7 +96,56 @@ func (gs *GameState) Location(locationType LocationType) *Location {
 	return gs.Board.GetLocation(locationType)
 }
 
+// RecordIncident 记录事件在指定循环和日期发生
+func (gs *GameState) RecordIncident(loop, day int, incidentType IncidentType) {
+	key := IncidentKey{Loop: loop, Day: day}
+	gs.IncidentsOccurred[key] = append(gs.IncidentsOccurred[key], incidentType)
+}
+
+// RoleAbilityUse 身份能力使用记录的键
+type RoleAbilityUse struct {
+	Character CharacterName
+	Ability   RoleAbility
+}
+
+// CanUseRoleAbility 每循环一次的身份能力本循环未使用时才能发动
+func (gs *GameState) CanUseRoleAbility(character *Character, ability RoleAbility) bool {
+	limited, ok := ability.(OncePerLoopRoleAbility)
+	if !ok || !limited.OncePerLoop() {
+		return true
+	}
+	return !gs.UsedRoleAbilities[RoleAbilityUse{Character: character.Name, Ability: ability}]
+}
+
+// MarkRoleAbilityUsed 记录每循环一次的身份能力本循环已使用，新循环开始时清空
+func (gs *GameState) MarkRoleAbilityUsed(character *Character, ability RoleAbility) {
+	if limited, ok := ability.(OncePerLoopRoleAbility); !ok || !limited.OncePerLoop() {
+		return
+	}
+	if gs.UsedRoleAbilities == nil {
+		gs.UsedRoleAbilities = make(map[RoleAbilityUse]bool)
+	}
+	gs.UsedRoleAbilities[RoleAbilityUse{Character: character.Name, Ability: ability}] = true
+}
+
+// IncidentOccurred 事件是否在指定循环发生过
+func (gs *GameState) IncidentOccurred(loop int, incidentType IncidentType) bool {
+	for key, types := range gs.IncidentsOccurred {
+		if key.Loop != loop {
+			continue
+		}
+		for _, t := range types {
+			if t == incidentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // PrintGameState 详细打印游戏状态信息
+//
+// 日志与主角方看到的信息相同，不写入剧情、身份和面朝下的行动卡内容。
 func (gs *GameState) PrintGameState() {
 	if gs.logging == nil {
 		return
@@ -87,25 +158,17 @@ func (gs *GameState) PrintGameState() {
 		zap.Int("Current Loop", gs.CurrentLoop),
 		zap.Int("Days Per Loop", gs.Script.DaysPerLoop),
 		zap.Int("Current Day", gs.CurrentDay),
-		zap.String("Current Phase", string(gs.CurrentPhase)),
+		zap.String("Current Game Phase", string(gs.CurrentGamePhase)),
+		zap.String("Current Loop Phase", string(gs.CurrentLoopPhase)),
+		zap.String("Current Day Phase", string(gs.CurrentDayPhase)),
 		zap.Bool("Is Game Over", gs.IsGameOver),
 		zap.String("Winner", gs.WinnerType),
 		zap.Bool("Final Guess Made", gs.GuessMade))
 
-	// 2. 剧本信息
+	// 2. 剧本信息，剧情属于非公开信息不写入日志
 	if gs.Script != nil {
-		mainPlotName := ""
-		if gs.Script.MainPlot != nil {
-			mainPlotName = gs.Script.MainPlot.Name
-		}
-		subPlotNames := make([]string, 0)
-		for _, subplot := range gs.Script.SubPlots {
-			subPlotNames = append(subPlotNames, subplot.Name)
-		}
-
 		gs.logging.Debug("Script Information",
-			zap.String("Main Plot", mainPlotName),
-			zap.Strings("Sub Plots", subPlotNames),
+			zap.String("Title", gs.Script.Title),
 			zap.Int("Maximum Loops", gs.Script.MaxLoops),
 			zap.Int("Days Per Loop", gs.Script.DaysPerLoop))
 	}
@@ -117,15 +180,10 @@ func (gs *GameState) PrintGameState() {
 			continue
 		}
 
-		roleType := "Person"
-		if char.Role() != nil {
-			roleType = string(char.Role().Type)
-		}
-
-		// 角色基本信息
+		// 角色基本信息，身份属于非公开信息不写入日志
 		gs.logging.Debug("Character Details",
 			zap.String("Name", string(char.Name)),
-			zap.String("Role", roleType),
+			zap.Bool("Role Revealed", gs.RoleRevealed(char.Name)),
 			zap.String("Current Location", string(char.Location())),
 			zap.String("Starting Location", string(char.StartLocation)),
 			zap.Bool("Is Alive", char.IsAlive()))
@@ -153,8 +211,7 @@ func (gs *GameState) PrintGameState() {
 	// 4. 位置信息
 	gs.logging.Debug("---------- Location Status ----------")
 	if gs.Board != nil {
-		locations := []LocationType{LocationHospital, LocationCity, LocationSchool, LocationShrine}
-		for _, locType := range locations {
+		for _, locType := range gs.Board.Locations() {
 			loc := gs.Board.GetLocation(locType)
 			if loc == nil {
 				continue
@@ -196,56 +253,20 @@ func (gs *GameState) PrintGameState() {
 	// 7. Action Cards Status
 	gs.logging.Debug("---------- Action Cards Status ----------")
 
-	// 当前回合的行动卡
-	if gs.Board != nil && gs.Board.actionCards != nil {
+	// 当前回合的行动卡，面朝下的卡牌只写入所有者和目标
+	if gs.Board != nil {
 		gs.logging.Debug("Current Action Cards on Board")
-
-		// 按优先级分类打印卡牌
-		// 1. Forbid Movement cards
-		// 2. Movement cards
-		// 3. Other Forbid cards
-		// 4. Other remaining action cards
-
-		// 创建分类映射
-		cardsByType := make(map[CardType][]Card)
 		for _, card := range gs.Board.actionCards {
-			cardsByType[card.Type()] = append(cardsByType[card.Type()], card)
-		}
-
-		// 按照规则顺序打印
-		priorityOrder := []CardType{
-			ForbidMovementType, // 1st: Forbid Movement
-			MovementType,       // 2nd: Movement
-			ForbidIntrigueType, // 3rd: Other Forbid cards
-			ForbidParanoiaType,
-			ForbidGoodwillType,
-			IntrigueType, // 4th: Other remaining cards
-			ParanoiaType,
-			GoodwillType,
-		}
-
-		for _, cardType := range priorityOrder {
-			if cards, exists := cardsByType[cardType]; exists {
-				for _, card := range cards {
-					targetInfo := "Unknown"
-					if target := card.Target(); target != nil {
-						switch t := target.(type) {
-						case *Character:
-							targetInfo = fmt.Sprintf("Character: %s", t.Name)
-						case *Location:
-							targetInfo = fmt.Sprintf("Location: %s", t.LocationType)
-						}
-					}
-
-					gs.logging.Debug("Action Card",
-						zap.String("Type", string(card.Type())),
-						zap.String("ID", card.Id()),
-						zap.String("Target", targetInfo),
-						zap.Int("Priority", card.Priority()),
-						zap.Bool("OncePerLoop", card.IsOncePerLoop()),
-						zap.Reflect("Owner", card.Owner()))
-				}
+			cv := cardView(card, ProtagonistViewOptions())
+			fields := []zap.Field{
+				zap.String("Owner", string(cv.Owner)),
+				zap.String("Target", cv.Target),
+				zap.Bool("FaceDown", cv.FaceDown),
+			}
+			if !cv.Hidden {
+				fields = append(fields, zap.String("Type", string(cv.Type)), zap.String("ID", cv.ID))
 			}
+			gs.logging.Debug("Action Card", fields...)
 		}
 	}
 
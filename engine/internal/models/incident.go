@@ -6,11 +6,70 @@ import (
 
 type IncidentType string
 
-type IncidentEffectTarget interface {
+// IncidentContext 事件发生时的上下文，作为事件效果的目标
+type IncidentContext struct {
+	Loop    int        // 发生的循环
+	Day     int        // 发生的日期
+	Culprit *Character // 事件当事人
+	Target  TargetType // 幕后主使选择的目标，不需要选择时为空
+	// Targets 需要依次选择多个目标的事件所选的全部目标，Target 为其中第一个
+	Targets []TargetType
 }
 
 type Incident interface {
 	Type() IncidentType
-	Execute(logger zap.Logger, gameState *GameState, target IncidentEffectTarget) error
-	IsTriggerable(logger zap.Logger, gameState *GameState, target IncidentEffectTarget) bool
+	Execute(logger zap.Logger, gameState *GameState, target *IncidentContext) error
+	IsTriggerable(logger zap.Logger, gameState *GameState, target *IncidentContext) bool
+}
+
+// IncidentTargetSelector 需要幕后主使选择目标的事件
+type IncidentTargetSelector interface {
+	Incident
+	// TargetOptions 返回事件当前可选的目标
+	TargetOptions(gameState *GameState, culprit *Character) []TargetType
+}
+
+// IncidentMultiTargetSelector 需要幕后主使依次选择多个目标的事件
+type IncidentMultiTargetSelector interface {
+	Incident
+	// TargetCount 返回需要依次选择的目标数量
+	TargetCount() int
+	// NextTargetOptions 返回已经选择 chosen 之后下一个目标的可选项
+	NextTargetOptions(gameState *GameState, culprit *Character, chosen []TargetType) []TargetType
+}
+
+// ScheduledIncident 剧本中安排在某一天、由某个角色作为当事人的事件
+type ScheduledIncident struct {
+	Incident Incident
+	Day      int           // 发生的日期
+	Culprit  CharacterName // 当事人
+}
+
+// Type 返回事件类型
+func (s *ScheduledIncident) Type() IncidentType {
+	return s.Incident.Type()
+}
+
+// CanOccur 判断事件今天是否会发生：日期相符，当事人存活且不安值达到上限
+func (s *ScheduledIncident) CanOccur(gameState *GameState) bool {
+	if s.Day != gameState.CurrentDay {
+		return false
+	}
+	culprit := gameState.Character(s.Culprit)
+	return culprit != nil && culprit.IsAlive() && culprit.HasReachedParanoiaLimit()
+}
+
+// Context 构建事件在当前循环与日期的上下文
+func (s *ScheduledIncident) Context(gameState *GameState) *IncidentContext {
+	return &IncidentContext{
+		Loop:    gameState.CurrentLoop,
+		Day:     gameState.CurrentDay,
+		Culprit: gameState.Character(s.Culprit),
+	}
+}
+
+// IncidentKey 事件发生记录的键
+type IncidentKey struct {
+	Loop int
+	Day  int
 }
@@ -1,7 +1,5 @@
 package models
 
-import "fmt"
-
 // LocationType 代表游戏板上的位置类型
 type LocationType string
 
@@ -10,6 +8,7 @@ const (
 	LocationCity     LocationType = "City"     // 城市
 	LocationSchool   LocationType = "School"   // 学校
 	LocationShrine   LocationType = "Shrine"   // 神社
+	LocationFaraway  LocationType = "Faraway"  // 远方（扩展地图，不在网格中）
 )
 
 // Location 代表游戏板上的一个具体位置
@@ -18,12 +17,7 @@ type Location struct {
 	Attributes   Attributes                   // 位置属性值
 	Characters   map[CharacterName]*Character // 当前在此位置的角色
 
-	// 相邻位置关系
-	left     *Location // 左侧位置
-	right    *Location // 右侧位置
-	top      *Location // 上方位置
-	bottom   *Location // 下方位置
-	diagonal *Location // 斜向位置
+	events *EventBus // 计数器变化发布到的事件总线
 }
 
 func (l *Location) Intrigue() int {
@@ -65,7 +59,11 @@ func (l *Location) SetAttribute(attr AttributeType, value int) {
 		if value < 0 {
 			value = 0
 		}
+		old := l.Attributes.Get(attr)
 		l.Attributes.Set(attr, value)
+		if old != value {
+			l.events.Publish(&CounterChanged{Target: RefOf(l), Attribute: attr, Old: old, New: value})
+		}
 	}
 	// 其他attr忽略，保持原SetParanoia/SetGoodwill逻辑
 }
@@ -74,57 +72,6 @@ func (l *Location) Location() LocationType {
 	return l.LocationType
 }
 
+// ToLocation 位置本身不会移动，移动卡也不能放置在位置上
 func (l *Location) ToLocation(board *Board, movementDirection MovementDirection) {
 }
-
-// getNextLocation determines the next location based on movement direction
-func (l *Location) getNextLocation(movementDirection MovementDirection) (*Location, error) {
-	switch movementDirection {
-	case HorizontalMovement:
-		return l.getHorizontalLocation()
-	case VerticalMovement:
-		return l.getVerticalLocation()
-	case DiagonalMovement:
-		return l.getDiagonalLocation()
-	default:
-		return nil, fmt.Errorf("invalid movement movementDirection")
-	}
-}
-
-// getHorizontalLocation handles horizontal movement logic
-func (l *Location) getHorizontalLocation() (*Location, error) {
-	if l.left == nil && l.right == nil {
-		return nil, fmt.Errorf("invalid map definition: no horizontal movement possible")
-	}
-	if l.left != nil && l.right != nil {
-		return nil, fmt.Errorf("invalid map definition: multiple horizontal paths")
-	}
-
-	if l.left != nil {
-		return l.left, nil
-	}
-	return l.right, nil
-}
-
-// getVerticalLocation handles vertical movement logic
-func (l *Location) getVerticalLocation() (*Location, error) {
-	if l.top == nil && l.bottom == nil {
-		return nil, fmt.Errorf("invalid map definition: no vertical movement possible")
-	}
-	if l.top != nil && l.bottom != nil {
-		return nil, fmt.Errorf("invalid map definition: multiple vertical paths")
-	}
-
-	if l.top != nil {
-		return l.top, nil
-	}
-	return l.bottom, nil
-}
-
-// getDiagonalLocation handles diagonal movement logic
-func (l *Location) getDiagonalLocation() (*Location, error) {
-	if l.diagonal == nil {
-		return nil, fmt.Errorf("invalid map definition: no diagonal movement possible")
-	}
-	return l.diagonal, nil
-}
@@ -0,0 +1,50 @@
+package models
+
+import "go.uber.org/zap"
+
+// LoopEndCause 循环提前结束的原因
+type LoopEndCause string
+
+const (
+	LoopEndProtagonistsDead LoopEndCause = "ProtagonistsDead" // 主角死亡
+	LoopEndKeyPersonDead    LoopEndCause = "KeyPersonDead"    // 关键人物死亡
+)
+
+// EarlyLoopEnd 循环提前结束的记录
+type EarlyLoopEnd struct {
+	Loop   int
+	Day    int
+	Phase  DayPhase     // 触发时所在的阶段
+	Cause  LoopEndCause // 结束原因
+	Source string       // 触发的实体，如角色名或事件类型
+}
+
+// EndLoopEarly 立即结束当前循环，主角方在本循环失败；同一循环内只记录第一次
+func (gs *GameState) EndLoopEarly(cause LoopEndCause, source string) {
+	if gs.LoopEnd != nil {
+		return
+	}
+	gs.LoopEnd = &EarlyLoopEnd{
+		Loop:   gs.CurrentLoop,
+		Day:    gs.CurrentDay,
+		Phase:  gs.CurrentDayPhase,
+		Cause:  cause,
+		Source: source,
+	}
+	gs.LoseLoop(&LoopLoss{Reason: string(cause)})
+	gs.logging.Info("Loop ended early",
+		zap.String("cause", string(cause)),
+		zap.String("source", source),
+		zap.Int("loop", gs.CurrentLoop),
+		zap.Int("day", gs.CurrentDay))
+}
+
+// KillProtagonists 主角死亡，循环立即结束
+func (gs *GameState) KillProtagonists(source string) {
+	gs.EndLoopEarly(LoopEndProtagonistsDead, source)
+}
+
+// LoopEndedEarly 当前循环是否已提前结束
+func (gs *GameState) LoopEndedEarly() bool {
+	return gs.LoopEnd != nil
+}
@@ -0,0 +1,131 @@
+package models
+
+import "go.uber.org/zap"
+
+// MovementBlockReason 合并后的移动没有发生的原因
+type MovementBlockReason string
+
+const (
+	MovementCancelled     MovementBlockReason = "DirectionsCancelled" // 相同方向的移动互相抵消
+	MovementForbidden     MovementBlockReason = "ForbiddenLocation"   // 目标位置禁止该角色进入
+	MovementNoDestination MovementBlockReason = "NoDestination"       // 地图上没有对应方向的位置
+)
+
+// MovementResult 一个角色本次结算的合并移动结果
+type MovementResult struct {
+	Character   *Character
+	Cards       []Card              // 作用于该角色的移动卡
+	Direction   MovementDirection   // 合并后的移动方向，抵消时为空
+	Destination LocationType        // 合并方向指向的位置
+	From        LocationType        // 移动前的位置
+	To          LocationType        // 移动后的位置，未移动时与 From 相同
+	Moved       bool                // 是否实际移动
+	Reason      MovementBlockReason // 未移动的原因
+}
+
+// movementAxes 移动方向对应的横纵分量，横向+纵向=斜向，同向两次抵消
+var movementAxes = map[MovementDirection]int{
+	HorizontalMovement: 1,
+	VerticalMovement:   2,
+	DiagonalMovement:   3,
+}
+
+// CombineMovement 将多张移动卡的方向合并为一次净移动，完全抵消时返回空字符串
+func CombineMovement(directions ...MovementDirection) MovementDirection {
+	net := 0
+	for _, direction := range directions {
+		net ^= movementAxes[direction]
+	}
+	for direction, axes := range movementAxes {
+		if axes == net {
+			return direction
+		}
+	}
+	return ""
+}
+
+// resolveMovement 合并每个角色身上所有未被无效化的移动卡，并执行一次净移动
+func (board *Board) resolveMovement(resolution *Resolution) {
+	for _, result := range resolution.Results {
+		card, ok := result.Card.(*MovementCard)
+		if !ok || result.Negated {
+			continue
+		}
+		char, ok := card.Target().(*Character)
+		if !ok {
+			continue
+		}
+
+		var movement *MovementResult
+		for _, m := range resolution.Movements {
+			if m.Character == char {
+				movement = m
+				break
+			}
+		}
+		if movement == nil {
+			movement = &MovementResult{Character: char}
+			resolution.Movements = append(resolution.Movements, movement)
+		}
+		movement.Cards = append(movement.Cards, card)
+	}
+
+	for _, movement := range resolution.Movements {
+		directions := make([]MovementDirection, 0, len(movement.Cards))
+		for _, card := range movement.Cards {
+			directions = append(directions, card.(*MovementCard).Direction)
+		}
+		board.applyMovement(movement, CombineMovement(directions...))
+	}
+}
+
+// moveCharacter 按方向找到目标位置，检查禁止位置后移动角色
+func (board *Board) moveCharacter(movement *MovementResult, direction MovementDirection) {
+	char := movement.Character
+	next, err := board.Destination(movement.From, direction)
+	if err != nil {
+		movement.Reason = MovementNoDestination
+		return
+	}
+
+	movement.Destination = next.LocationType
+	if !char.CanMoveTo(next.LocationType) {
+		movement.Reason = MovementForbidden
+		return
+	}
+
+	if err = board.MoveTo(char, next.LocationType); err != nil {
+		movement.Reason = MovementForbidden
+		return
+	}
+	movement.To = next.LocationType
+	movement.Moved = true
+}
+
+// applyMovement 按合并后的方向移动角色，并记录未移动的原因
+func (board *Board) applyMovement(movement *MovementResult, direction MovementDirection) {
+	char := movement.Character
+	movement.Direction = direction
+	movement.From = char.Location()
+	movement.To = movement.From
+
+	if direction == "" {
+		movement.Reason = MovementCancelled
+	} else {
+		board.moveCharacter(movement, direction)
+	}
+
+	if !movement.Moved {
+		board.logging.Debug("Character did not move",
+			zap.String("character", string(char.Name)),
+			zap.String("direction", string(direction)),
+			zap.String("destination", string(movement.Destination)),
+			zap.String("reason", string(movement.Reason)))
+		return
+	}
+	board.logging.Debug("Character has been moved",
+		zap.String("character", string(char.Name)),
+		zap.String("direction", string(direction)),
+		zap.String("from", string(movement.From)),
+		zap.String("to", string(movement.To)))
+}
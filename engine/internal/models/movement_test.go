@@ -0,0 +1,138 @@
+package models
+
+import "testing"
+
+func TestCombineMovement(t *testing.T) {
+	tests := []struct {
+		name       string
+		directions []MovementDirection
+		want       MovementDirection
+	}{
+		{name: "no cards", want: ""},
+		{name: "single card", directions: []MovementDirection{VerticalMovement}, want: VerticalMovement},
+		{name: "horizontal and vertical", directions: []MovementDirection{HorizontalMovement, VerticalMovement}, want: DiagonalMovement},
+		{name: "same direction cancels", directions: []MovementDirection{HorizontalMovement, HorizontalMovement}, want: ""},
+		{name: "diagonal and horizontal", directions: []MovementDirection{DiagonalMovement, HorizontalMovement}, want: VerticalMovement},
+		{name: "diagonal and vertical", directions: []MovementDirection{DiagonalMovement, VerticalMovement}, want: HorizontalMovement},
+		{name: "three directions cancel", directions: []MovementDirection{HorizontalMovement, VerticalMovement, DiagonalMovement}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CombineMovement(tt.directions...); got != tt.want {
+				t.Errorf("CombineMovement(%v) = %q, want %q", tt.directions, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveMovement(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(f *boardFixture)
+		placed func(f *boardFixture) []CardPlacement
+		// moved 为 false 时 Boy 没有移动结果
+		moved     bool
+		direction MovementDirection
+		to        LocationType
+		reason    MovementBlockReason
+	}{
+		{
+			name: "single card",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{{Card: f.card(f.mastermind, MovementType, 0), Target: f.character("Boy")}}
+			},
+			moved: true, direction: HorizontalMovement, to: LocationCity,
+		},
+		{
+			name: "horizontal and vertical move diagonally",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, MovementType, 0), Target: f.character("Boy")},
+					{Card: f.card(f.protagonists[0], MovementType, 1), Target: f.character("Boy")},
+				}
+			},
+			moved: true, direction: DiagonalMovement, to: LocationHospital,
+		},
+		{
+			name: "diagonal and horizontal move vertically",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, MovementType, 2), Target: f.character("Boy")},
+					{Card: f.card(f.protagonists[0], MovementType, 0), Target: f.character("Boy")},
+				}
+			},
+			moved: true, direction: VerticalMovement, to: LocationShrine,
+		},
+		{
+			name: "same direction cancels",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, MovementType, 0), Target: f.character("Boy")},
+					{Card: f.card(f.protagonists[0], MovementType, 0), Target: f.character("Boy")},
+				}
+			},
+			to: LocationSchool, reason: MovementCancelled,
+		},
+		{
+			name: "forbidden destination",
+			setup: func(f *boardFixture) {
+				boy := f.character("Boy")
+				boy.ForbiddenLocations = append(boy.ForbiddenLocations, LocationCity)
+			},
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{{Card: f.card(f.mastermind, MovementType, 0), Target: f.character("Boy")}}
+			},
+			direction: HorizontalMovement, to: LocationSchool, reason: MovementForbidden,
+		},
+		{
+			name: "negated card does not move",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, MovementType, 0), Target: f.character("Boy")},
+					{Card: f.card(f.protagonists[0], ForbidMovementType, 0), Target: f.character("Boy")},
+				}
+			},
+			to: LocationSchool,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBoardFixture(t)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			f.place(tt.placed(f)...)
+
+			resolution, err := f.gs.Board.ResolveActionCards(f.gs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			boy := f.character("Boy")
+			if got := boy.Location(); got != tt.to {
+				t.Errorf("Boy is at %s, want %s", got, tt.to)
+			}
+
+			var movement *MovementResult
+			for _, m := range resolution.Movements {
+				if m.Character == boy {
+					movement = m
+				}
+			}
+			if movement == nil {
+				if tt.direction != "" || tt.reason != "" {
+					t.Fatal("no movement result for Boy")
+				}
+				return
+			}
+			if movement.Moved != tt.moved || movement.Direction != tt.direction || movement.Reason != tt.reason {
+				t.Errorf("movement = moved %v, %q, reason %q; want moved %v, %q, reason %q",
+					movement.Moved, movement.Direction, movement.Reason, tt.moved, tt.direction, tt.reason)
+			}
+			if movement.From != LocationSchool || movement.To != tt.to {
+				t.Errorf("movement from %s to %s, want School to %s", movement.From, movement.To, tt.to)
+			}
+		})
+	}
+}
@@ -13,15 +13,15 @@ const (
 	PhaseGameEnd         GamePhase = "GameEnd"         // 游戏结束
 )
 
-// LoopPhase 表示一个循环内的各个阶段
+// LoopPhase 表示一个循环内的各个阶段，按进行顺序排列
 type LoopPhase string
 
 const (
-	PhaseLoopStart      LoopPhase = "LoopStart"      // 循环开始
 	PhaseTimeSpiral     LoopPhase = "TimeSpiral"     // Time Spiral讨论阶段
 	PhaseCharacterReset LoopPhase = "CharacterReset" // 角色归位阶段
 	PhaseCountersReset  LoopPhase = "CountersReset"  // 移除和替换计数器阶段
 	PhaseReturnCards    LoopPhase = "ReturnCards"    // 玩家取回卡牌阶段
+	PhaseLoopStart      LoopPhase = "LoopStart"      // 循环开始，发动循环开始时的能力
 	PhaseDay            LoopPhase = "Day"            // 每日流程阶段(包含多天)
 	PhaseLoopEnd        LoopPhase = "LoopEnd"        // 循环结束，检查胜利条件
 )
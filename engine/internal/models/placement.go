@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidPlacement 卡牌或目标为空
+	ErrInvalidPlacement = errors.New("invalid card placement")
+	// ErrCardTypeNotAllowed 该类型的卡牌不能放置在此目标上，例如对位置放置不安卡
+	ErrCardTypeNotAllowed = errors.New("card type cannot be placed on this target")
+	// ErrTargetDead 目标角色已经死亡
+	ErrTargetDead = errors.New("target character is dead")
+	// ErrTargetOccupied 目标上已有不允许共存的卡牌
+	ErrTargetOccupied = errors.New("target already holds a card")
+	// ErrCardLimitReached 玩家今天已放置了最大数量的卡牌
+	ErrCardLimitReached = errors.New("card limit for this day reached")
+	// ErrCardAlreadyPlaced 卡牌已经放置在游戏板上
+	ErrCardAlreadyPlaced = errors.New("card already placed")
+	// ErrCardNotInHand 卡牌不在玩家手中
+	ErrCardNotInHand = errors.New("card not in hand")
+)
+
+// PlacementError 卡牌放置失败时返回的错误，可通过 errors.Is 判断具体原因
+type PlacementError struct {
+	Card   Card
+	Target TargetType
+	Err    error
+}
+
+func (e *PlacementError) Error() string {
+	if e.Card == nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("cannot place %s: %v", e.Card.Id(), e.Err)
+}
+
+func (e *PlacementError) Unwrap() error {
+	return e.Err
+}
+
+// CheckPlacement 按规则检查卡牌是否可以放置到目标上，不修改游戏板
+//
+// 幕后主使每天在三个不同目标上各放置一张卡牌；
+// 每位主角放置一张卡牌，且目标上不能已有其他主角的卡牌。
+func (board *Board) CheckPlacement(target TargetType, card Card) error {
+	return board.checkPlacement(target, card, nil)
+}
+
+// CheckPlacements 检查一批卡牌放置，批次中排在前面的卡牌视为已经放置
+//
+// 整批合法时才应放置任何一张卡牌，避免部分放置后无法重试。
+func (board *Board) CheckPlacements(placements []CardPlacement) error {
+	for i, placement := range placements {
+		if err := board.checkPlacement(placement.Target, placement.Card, placements[:i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPlacement 检查卡牌放置，pending 为同一批次中尚未放到游戏板上的卡牌
+func (board *Board) checkPlacement(target TargetType, card Card, pending []CardPlacement) error {
+	fail := func(err error) error {
+		return &PlacementError{Card: card, Target: target, Err: err}
+	}
+
+	if card == nil || target == nil {
+		return fail(ErrInvalidPlacement)
+	}
+	if !card.IsValidTarget(target) {
+		return fail(fmt.Errorf("%w: %s", ErrCardTypeNotAllowed, card.Type()))
+	}
+	if char, ok := target.(*Character); ok && !char.IsAlive() {
+		return fail(fmt.Errorf("%w: %s", ErrTargetDead, char.Name))
+	}
+
+	existing := make([]CardPlacement, 0, len(board.actionCards)+len(pending))
+	for _, c := range board.actionCards {
+		existing = append(existing, CardPlacement{Card: c, Target: c.Target()})
+	}
+	existing = append(existing, pending...)
+
+	placed := 0
+	for _, other := range existing {
+		if other.Card == card {
+			return fail(ErrCardAlreadyPlaced)
+		}
+		if !sameSide(other.Card.Owner(), card.Owner()) {
+			continue
+		}
+		if other.Card.Owner() == card.Owner() {
+			placed++
+		}
+		if other.Target == target {
+			return fail(ErrTargetOccupied)
+		}
+	}
+
+	if limit := maxCardsPerDay(card.Owner()); placed >= limit {
+		return fail(fmt.Errorf("%w: %d", ErrCardLimitReached, limit))
+	}
+	return nil
+}
+
+// sameSide 两名玩家是否属于同一方
+func sameSide(a, b Player) bool {
+	return SeatOf(a) == SeatOf(b)
+}
+
+// maxCardsPerDay 返回玩家每天可以放置的卡牌数量
+func maxCardsPerDay(player Player) int {
+	switch p := player.(type) {
+	case *Mastermind:
+		return p.MaxCardsPerDay
+	case *Protagonist:
+		return p.MaxCardsPerDay
+	default:
+		return 0
+	}
+}
+
+// isCharacterTarget 目标是否为角色
+func isCharacterTarget(target TargetType) bool {
+	_, ok := target.(*Character)
+	return ok
+}
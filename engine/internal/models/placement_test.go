@@ -0,0 +1,261 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// boardFixture 三个角色、一名幕后主使和三名主角的游戏板
+type boardFixture struct {
+	t            *testing.T
+	gs           *GameState
+	mastermind   *Mastermind
+	protagonists Protagonists
+}
+
+func newBoardFixture(t *testing.T) *boardFixture {
+	t.Helper()
+	gs := NewGameState(zap.NewNop())
+	for _, data := range []*CharacterData{
+		{Name: "Boy", StartLocation: LocationSchool, GoodwillLimit: 4, ParanoiaLimit: 3},
+		{Name: "Girl", StartLocation: LocationSchool, GoodwillLimit: 4, ParanoiaLimit: 3},
+		{Name: "Doctor", StartLocation: LocationHospital, GoodwillLimit: 4, ParanoiaLimit: 3},
+	} {
+		gs.Characters = append(gs.Characters, NewCharacter(data, nil))
+	}
+	gs.Board = NewBoard(zap.NewNop(), gs.Characters)
+	if err := gs.Board.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	gs.Mastermind = NewMastermind()
+	gs.Protagonists = Protagonists{NewProtagonist("P1", true), NewProtagonist("P2", false), NewProtagonist("P3", false)}
+	gs.CurrentLoop, gs.CurrentDay = 1, 1
+	return &boardFixture{t: t, gs: gs, mastermind: gs.Mastermind, protagonists: gs.Protagonists}
+}
+
+func (f *boardFixture) character(name CharacterName) *Character {
+	f.t.Helper()
+	char := f.gs.Character(name)
+	if char == nil {
+		f.t.Fatalf("unknown character %s", name)
+	}
+	return char
+}
+
+func (f *boardFixture) location(locationType LocationType) *Location {
+	f.t.Helper()
+	location := f.gs.Location(locationType)
+	if location == nil {
+		f.t.Fatalf("unknown location %s", locationType)
+	}
+	return location
+}
+
+// card 返回玩家手牌中第 n 张指定类型的卡牌
+func (f *boardFixture) card(player Player, cardType CardType, n int) Card {
+	f.t.Helper()
+	for _, card := range player.GetHandCards() {
+		if card.Type() != cardType {
+			continue
+		}
+		if n == 0 {
+			return card
+		}
+		n--
+	}
+	f.t.Fatalf("%s has no such %s card", SeatOf(player), cardType)
+	return nil
+}
+
+// place 放置卡牌，放置失败时测试失败
+func (f *boardFixture) place(placements ...CardPlacement) {
+	f.t.Helper()
+	for _, placement := range placements {
+		if err := f.gs.Board.SetCard(placement.Target, placement.Card); err != nil {
+			f.t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckPlacement(t *testing.T) {
+	tests := []struct {
+		name string
+		// placed 检查前已经放置的卡牌
+		placed func(f *boardFixture) []CardPlacement
+		// check 需要检查的放置
+		check func(f *boardFixture) CardPlacement
+		err   error
+	}{
+		{
+			name: "intrigue on a location",
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.mastermind, IntrigueType, 0), Target: f.location(LocationSchool)}
+			},
+		},
+		{
+			name: "intrigue on a character",
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.mastermind, IntrigueType, 0), Target: f.character("Boy")}
+			},
+		},
+		{
+			name: "paranoia on a location",
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.mastermind, ParanoiaType, 0), Target: f.location(LocationSchool)}
+			},
+			err: ErrCardTypeNotAllowed,
+		},
+		{
+			name: "goodwill on a location",
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.protagonists[0], GoodwillType, 0), Target: f.location(LocationShrine)}
+			},
+			err: ErrCardTypeNotAllowed,
+		},
+		{
+			name: "movement on a location",
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.mastermind, MovementType, 0), Target: f.location(LocationCity)}
+			},
+			err: ErrCardTypeNotAllowed,
+		},
+		{
+			name: "forbid movement on a location",
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.protagonists[0], ForbidMovementType, 0), Target: f.location(LocationCity)}
+			},
+			err: ErrCardTypeNotAllowed,
+		},
+		{
+			name: "dead character",
+			placed: func(f *boardFixture) []CardPlacement {
+				if _, err := f.gs.KillCharacter(f.character("Girl"), DeathByEffect, "test"); err != nil {
+					f.t.Fatal(err)
+				}
+				return nil
+			},
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.mastermind, ParanoiaType, 0), Target: f.character("Girl")}
+			},
+			err: ErrTargetDead,
+		},
+		{
+			name: "no target",
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.mastermind, ParanoiaType, 0)}
+			},
+			err: ErrInvalidPlacement,
+		},
+		{
+			name: "same card twice",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{{Card: f.card(f.mastermind, IntrigueType, 0), Target: f.character("Boy")}}
+			},
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.mastermind, IntrigueType, 0), Target: f.character("Girl")}
+			},
+			err: ErrCardAlreadyPlaced,
+		},
+		{
+			name: "mastermind uses a target twice",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{{Card: f.card(f.mastermind, IntrigueType, 0), Target: f.character("Boy")}}
+			},
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.mastermind, ParanoiaType, 0), Target: f.character("Boy")}
+			},
+			err: ErrTargetOccupied,
+		},
+		{
+			name: "mastermind places a fourth card",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, IntrigueType, 0), Target: f.character("Boy")},
+					{Card: f.card(f.mastermind, ParanoiaType, 0), Target: f.character("Girl")},
+					{Card: f.card(f.mastermind, ParanoiaType, 1), Target: f.character("Doctor")},
+				}
+			},
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.mastermind, IntrigueType, 1), Target: f.location(LocationShrine)}
+			},
+			err: ErrCardLimitReached,
+		},
+		{
+			name: "protagonist joins a mastermind card",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{{Card: f.card(f.mastermind, ParanoiaType, 0), Target: f.character("Boy")}}
+			},
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.protagonists[0], ParanoiaType, 1), Target: f.character("Boy")}
+			},
+		},
+		{
+			name: "protagonist joins another protagonist's card",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{{Card: f.card(f.protagonists[0], GoodwillType, 0), Target: f.character("Boy")}}
+			},
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.protagonists[1], ParanoiaType, 1), Target: f.character("Boy")}
+			},
+			err: ErrTargetOccupied,
+		},
+		{
+			name: "protagonist places a second card",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{{Card: f.card(f.protagonists[0], GoodwillType, 0), Target: f.character("Boy")}}
+			},
+			check: func(f *boardFixture) CardPlacement {
+				return CardPlacement{Card: f.card(f.protagonists[0], ParanoiaType, 1), Target: f.character("Girl")}
+			},
+			err: ErrCardLimitReached,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBoardFixture(t)
+			if tt.placed != nil {
+				f.place(tt.placed(f)...)
+			}
+			placement := tt.check(f)
+			err := f.gs.Board.CheckPlacement(placement.Target, placement.Card)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err == nil {
+				return
+			}
+			var placementErr *PlacementError
+			if !errors.As(err, &placementErr) || placementErr.Card != placement.Card {
+				t.Errorf("err %v does not identify the card", err)
+			}
+			// SetCard 按同样的规则拒绝放置
+			if err := f.gs.Board.SetCard(placement.Target, placement.Card); !errors.Is(err, tt.err) {
+				t.Errorf("SetCard err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCheckPlacementsBatch(t *testing.T) {
+	f := newBoardFixture(t)
+	batch := []CardPlacement{
+		{Card: f.card(f.mastermind, IntrigueType, 0), Target: f.location(LocationSchool)},
+		{Card: f.card(f.mastermind, ParanoiaType, 0), Target: f.character("Boy")},
+		{Card: f.card(f.mastermind, ParanoiaType, 1), Target: f.character("Boy")},
+	}
+	// 批次中排在前面的卡牌视为已经放置
+	if err := f.gs.Board.CheckPlacements(batch); !errors.Is(err, ErrTargetOccupied) {
+		t.Fatalf("err = %v, want %v", err, ErrTargetOccupied)
+	}
+	if cards := f.gs.Board.GetMastermindCards(); len(cards) != 0 {
+		t.Errorf("%d cards placed by a rejected check", len(cards))
+	}
+
+	batch[2].Target = f.character("Girl")
+	if err := f.gs.Board.CheckPlacements(batch); err != nil {
+		t.Fatal(err)
+	}
+}
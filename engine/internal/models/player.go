@@ -87,8 +87,9 @@ type Protagonists []*Protagonist
 
 // Protagonist 表示主角玩家
 type Protagonist struct {
-	PlayerBase      // 继承基础玩家属性
-	IsLeader   bool // 是否为当前领袖
+	PlayerBase        // 继承基础玩家属性
+	IsLeader   bool   // 是否为当前领袖
+	Controller string // 控制该牌组的玩家，少于三名玩家时一名玩家可控制多副牌组
 }
 
 // NewProtagonist 创建新的主角玩家
@@ -101,7 +102,8 @@ func NewProtagonist(id string, isLeader bool) *Protagonist {
 			OnceCards:      nil,
 			MaxCardsPerDay: 1,
 		},
-		IsLeader: isLeader,
+		IsLeader:   isLeader,
+		Controller: id,
 	}
 	protagonist.HandCards = InitProtagonistCard(protagonist)
 	return protagonist
@@ -112,15 +114,20 @@ func (protagonist *Protagonist) SetLeader(isLeader bool) {
 	protagonist.IsLeader = isLeader
 }
 
-// PassDeck 在领袖更替时将一副牌组传递给另一位主角玩家
+// PassDeck 将该牌组交给另一位主角的玩家控制
 func (protagonist *Protagonist) PassDeck(receiver *Protagonist) error {
-	if !protagonist.IsLeader {
-		return fmt.Errorf("只有领袖可以传递牌组")
+	if receiver == nil || receiver == protagonist {
+		return fmt.Errorf("无效的牌组接收者")
 	}
-	// 待实现：传递牌组的具体逻辑
+	protagonist.Controller = receiver.Controller
 	return nil
 }
 
+// SameController 两副牌组是否由同一名玩家控制
+func (protagonist *Protagonist) SameController(other *Protagonist) bool {
+	return other != nil && protagonist.Controller == other.Controller
+}
+
 // PlaceActionCards 允许主角玩家根据其控制的牌组数量打出牌
 func (protagonist *Protagonist) PlaceActionCards(state *GameState) error {
 	if len(protagonist.HandCards) < 1 {
@@ -129,13 +136,81 @@ func (protagonist *Protagonist) PlaceActionCards(state *GameState) error {
 	return nil
 }
 
-// MakeFinalGuess 实现Protagonists的最终猜测方法
-func (protagonists Protagonists) MakeFinalGuess(script *Script) (bool, error) {
-	return false, nil
+// MakeFinalGuess 校验并结算主角方的最终猜测，猜测必须包含每个角色
+//
+// 按正式规则，主角方必须猜对所有角色的身份才能获胜。
+func (protagonists Protagonists) MakeFinalGuess(gameState *GameState, guess map[CharacterName]RoleType) (*FinalGuessOutcome, error) {
+	if err := ValidateGuess(gameState, guess); err != nil {
+		return nil, err
+	}
+
+	outcome := &FinalGuessOutcome{Results: make([]*GuessResult, 0, len(gameState.Characters))}
+	for _, char := range gameState.Characters {
+		guessed := guess[char.Name]
+		actual := RolePersonType
+		if char.Role() != nil {
+			actual = char.Role().Type
+		}
+		result := &GuessResult{Character: char.Name, Guessed: guessed, Actual: actual, Correct: guessed == actual}
+		if result.Correct {
+			outcome.Correct++
+		}
+		outcome.Results = append(outcome.Results, result)
+	}
+	outcome.Win = outcome.Correct == len(outcome.Results)
+	return outcome, nil
 }
 
 // GetLeader 获取当前领袖
 func (protagonists Protagonists) GetLeader() *Protagonist {
+	for _, protagonist := range protagonists {
+		if protagonist.IsLeader {
+			return protagonist
+		}
+	}
+	return nil
+}
+
+// NextLeader 将领袖交给下一位主角并返回新的领袖
+func (protagonists Protagonists) NextLeader() *Protagonist {
+	if len(protagonists) == 0 {
+		return nil
+	}
+	next := protagonists[0]
+	for i, protagonist := range protagonists {
+		if protagonist.IsLeader {
+			next = protagonists[(i+1)%len(protagonists)]
+		}
+		protagonist.SetLeader(false)
+	}
+	next.SetLeader(true)
+	return next
+}
+
+// InLeaderOrder 从领袖开始按顺序返回所有主角，即放置行动卡的顺序
+func (protagonists Protagonists) InLeaderOrder() Protagonists {
+	start := 0
+	for i, protagonist := range protagonists {
+		if protagonist.IsLeader {
+			start = i
+			break
+		}
+	}
+	ordered := make(Protagonists, 0, len(protagonists))
+	ordered = append(ordered, protagonists[start:]...)
+	return append(ordered, protagonists[:start]...)
+}
+
+// AssignPlayers 按实际玩家人数分配牌组，多出的牌组依次交给前面的玩家控制
+func (protagonists Protagonists) AssignPlayers(count int) error {
+	if count < 1 || count > len(protagonists) {
+		return fmt.Errorf("主角玩家人数必须在1到%d之间", len(protagonists))
+	}
+	for i := count; i < len(protagonists); i++ {
+		if err := protagonists[i].PassDeck(protagonists[i%count]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -21,41 +22,51 @@ const (
 	Failure   RuleType = "FAILURE"   // 失败条件
 )
 
+// RuleTiming 剧情规则的判定时机
+type RuleTiming string
+
+const (
+	RuleTimingLoopStart           = RuleTiming(PhaseLoopStart)           // 循环开始
+	RuleTimingMastermindAbilities = RuleTiming(PhaseMastermindAbilities) // Mastermind能力阶段
+	RuleTimingDayEnd              = RuleTiming(PhaseDayEnd)              // 每天日落
+	RuleTimingLoopEnd             = RuleTiming(PhaseLoopEnd)             // 循环结束
+)
+
 // PlotRule 剧情规则接口
 type PlotRule interface {
 	CheckCondition(gameState *GameState) bool
-	GetTiming() DayPhase    // 游戏阶段
+	GetTiming() RuleTiming  // 判定时机
 	GetRuleType() RuleType  // 返回规则类型
 	GetDescription() string // 规则描述
 }
 
 // Plot 剧情结构体
 type Plot struct {
-	ID            string         // 剧情唯一标识符
-	Name          string         // 剧情名称
-	Type          PlotType       // 剧情类型（主要剧情或次要剧情）
-	Description   string         // 剧情描述
-	Rules         []PlotRule     // 剧情规则列表
-	RequiredRoles []string       // 必需角色列表
-	RoleCounts    map[string]int // 每个角色需要的数量
-	IsActive      bool           // 是否激活
-	CreatedAt     time.Time      // 创建时间
-	UpdatedAt     time.Time      // 更新时间
+	ID          string                 // 剧情唯一标识符
+	Name        string                 // 剧情名称
+	Type        PlotType               // 剧情类型（主要剧情或次要剧情）
+	Description string                 // 剧情描述
+	Rules       []PlotRule             // 剧情规则列表
+	Roles       []RoleType             // 剧情加入的身份，按添加顺序
+	RoleRanges  map[RoleType]RoleRange // 每个身份加入的数量范围
+	IsActive    bool                   // 是否激活
+	CreatedAt   time.Time              // 创建时间
+	UpdatedAt   time.Time              // 更新时间
 }
 
 // NewPlot 创建新剧情
 func NewPlot(id, name string, plotType PlotType, description string) *Plot {
 	return &Plot{
-		ID:            id,
-		Name:          name,
-		Type:          plotType,
-		Description:   description,
-		Rules:         make([]PlotRule, 0),
-		RequiredRoles: make([]string, 0),
-		RoleCounts:    make(map[string]int),
-		IsActive:      false,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		ID:          id,
+		Name:        name,
+		Type:        plotType,
+		Description: description,
+		Rules:       make([]PlotRule, 0),
+		Roles:       make([]RoleType, 0),
+		RoleRanges:  make(map[RoleType]RoleRange),
+		IsActive:    false,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
 }
 
@@ -65,9 +76,43 @@ func (p *Plot) AddRule(rule PlotRule) {
 	p.UpdatedAt = time.Now()
 }
 
-// 添加必需角色及其数量
-func (p *Plot) AddRequiredRole(roleID string, count int) {
-	p.RequiredRoles = append(p.RequiredRoles, roleID)
-	p.RoleCounts[roleID] = count
+// 添加必需身份及其数量，身份以 RoleType 标识
+func (p *Plot) AddRequiredRole(roleType RoleType, count int) {
+	p.addRole(roleType, RoleRange{Min: count, Max: count})
+}
+
+// 添加可选身份及其数量范围，如 0-2 个 Curmudgeon
+func (p *Plot) AddOptionalRole(roleType RoleType, min, max int) {
+	p.addRole(roleType, RoleRange{Min: min, Max: max})
+}
+
+func (p *Plot) addRole(roleType RoleType, r RoleRange) {
+	if _, ok := p.RoleRanges[roleType]; !ok {
+		p.Roles = append(p.Roles, roleType)
+	}
+	p.RoleRanges[roleType] = r
 	p.UpdatedAt = time.Now()
 }
+
+// RoleRange 身份数量的范围，Min 等于 Max 时数量固定
+type RoleRange struct {
+	Min int
+	Max int
+}
+
+// Contains 数量是否在范围内
+func (r RoleRange) Contains(count int) bool {
+	return count >= r.Min && count <= r.Max
+}
+
+// Add 合并两个范围，多个剧情加入同一身份时数量相加
+func (r RoleRange) Add(other RoleRange) RoleRange {
+	return RoleRange{Min: r.Min + other.Min, Max: r.Max + other.Max}
+}
+
+func (r RoleRange) String() string {
+	if r.Min == r.Max {
+		return fmt.Sprintf("%d", r.Min)
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
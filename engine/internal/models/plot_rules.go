@@ -0,0 +1,66 @@
+package models
+
+// LoopLoss 主角方在本循环失败的一条记录
+type LoopLoss struct {
+	Loop   int
+	Day    int
+	Plot   *Plot    // 触发失败的剧情，由身份能力导致时为空
+	Rule   PlotRule // 触发失败的规则，由身份能力导致时为空
+	Reason string   // 失败原因
+}
+
+// Plots 返回剧本的主剧情与所有子剧情
+func (s *Script) Plots() []*Plot {
+	plots := make([]*Plot, 0, len(s.SubPlots)+1)
+	if s.MainPlot != nil {
+		plots = append(plots, s.MainPlot)
+	}
+	for _, plot := range s.SubPlots {
+		if plot != nil {
+			plots = append(plots, plot)
+		}
+	}
+	return plots
+}
+
+// FailedRules 返回剧情中条件成立的失败规则
+func (p *Plot) FailedRules(gameState *GameState) []PlotRule {
+	failed := make([]PlotRule, 0)
+	for _, rule := range p.Rules {
+		if rule.GetRuleType() == Failure && rule.CheckCondition(gameState) {
+			failed = append(failed, rule)
+		}
+	}
+	return failed
+}
+
+// EvaluateRules 判定剧本中所有在该时机生效的失败规则，条件成立时记录主角方失败
+func (gs *GameState) EvaluateRules(timing RuleTiming) []*LoopLoss {
+	losses := make([]*LoopLoss, 0)
+	if gs.Script == nil {
+		return losses
+	}
+	for _, plot := range gs.Script.Plots() {
+		for _, rule := range plot.Rules {
+			if rule.GetRuleType() != Failure || rule.GetTiming() != timing || !rule.CheckCondition(gs) {
+				continue
+			}
+			loss := &LoopLoss{Plot: plot, Rule: rule, Reason: rule.GetDescription()}
+			gs.LoseLoop(loss)
+			losses = append(losses, loss)
+		}
+	}
+	return losses
+}
+
+// LoseLoop 记录主角方本循环失败
+func (gs *GameState) LoseLoop(loss *LoopLoss) {
+	loss.Loop = gs.CurrentLoop
+	loss.Day = gs.CurrentDay
+	gs.LoopLosses = append(gs.LoopLosses, loss)
+}
+
+// ProtagonistsLostLoop 主角方是否已在本循环失败
+func (gs *GameState) ProtagonistsLostLoop() bool {
+	return len(gs.LoopLosses) > 0
+}
@@ -0,0 +1,136 @@
+package models
+
+// NegationReason 卡牌被无效化的原因
+type NegationReason string
+
+const (
+	NegatedByForbid     NegationReason = "ForbiddenByCard" // 被同目标上对应的禁止卡无效化
+	NegatedByForbidPair NegationReason = "ForbidCancelled" // 同目标上的多张禁止卡互相抵消
+)
+
+// forbiddenCardTypes 禁止卡与其无效化的卡牌类型
+var forbiddenCardTypes = map[CardType]CardType{
+	ForbidMovementType: MovementType,
+	ForbidIntrigueType: IntrigueType,
+	ForbidParanoiaType: ParanoiaType,
+	ForbidGoodwillType: GoodwillType,
+}
+
+// IsForbidCard 是否为禁止卡
+func IsForbidCard(card Card) bool {
+	_, ok := forbiddenCardTypes[card.Type()]
+	return ok
+}
+
+// ForbidOverrideAbility 卡牌结算时可以无视禁止卡的身份能力，例如 Cultist
+type ForbidOverrideAbility interface {
+	RoleAbility
+	// IgnoresForbid 能力所属角色是否无视指定的禁止卡
+	IgnoresForbid(gameState *GameState, source *Character, forbid Card) bool
+}
+
+// CardResult 单张行动卡的结算结果
+type CardResult struct {
+	Card      Card
+	Target    TargetType
+	Negated   bool           // 是否被无效化
+	Reason    NegationReason // 被无效化的原因
+	NegatedBy []Card         // 导致无效化的卡牌
+	IgnoredBy *Character     // 无视禁止卡的角色
+}
+
+// Resolution 一次卡牌结算的完整记录
+type Resolution struct {
+	Results   []*CardResult
+	Movements []*MovementResult // 合并后的角色移动
+}
+
+// Result 返回指定卡牌的结算结果
+func (r *Resolution) Result(card Card) *CardResult {
+	for _, result := range r.Results {
+		if result.Card == card {
+			return result
+		}
+	}
+	return nil
+}
+
+// Negated 返回所有被无效化的卡牌结果
+func (r *Resolution) Negated() []*CardResult {
+	negated := make([]*CardResult, 0)
+	for _, result := range r.Results {
+		if result.Negated {
+			negated = append(negated, result)
+		}
+	}
+	return negated
+}
+
+// resolveForbids 按目标计算禁止卡的效果，返回每张卡牌的结算结果
+//
+// 同一目标上有多张禁止卡时全部互相抵消；
+// 其余禁止卡只无效化同目标上对应类型的卡牌，除非有身份能力无视该禁止卡。
+func resolveForbids(gs *GameState, cards []Card) *Resolution {
+	resolution := &Resolution{Results: make([]*CardResult, 0, len(cards))}
+	forbidsByTarget := make(map[TargetType][]Card)
+	for _, card := range cards {
+		resolution.Results = append(resolution.Results, &CardResult{Card: card, Target: card.Target()})
+		if IsForbidCard(card) {
+			forbidsByTarget[card.Target()] = append(forbidsByTarget[card.Target()], card)
+		}
+	}
+
+	for _, forbids := range forbidsByTarget {
+		if len(forbids) < 2 {
+			continue
+		}
+		for _, forbid := range forbids {
+			result := resolution.Result(forbid)
+			result.Negated = true
+			result.Reason = NegatedByForbidPair
+			for _, other := range forbids {
+				if other != forbid {
+					result.NegatedBy = append(result.NegatedBy, other)
+				}
+			}
+		}
+	}
+
+	for _, result := range resolution.Results {
+		if IsForbidCard(result.Card) {
+			continue
+		}
+		for _, forbid := range forbidsByTarget[result.Target] {
+			if resolution.Result(forbid).Negated || forbiddenCardTypes[forbid.Type()] != result.Card.Type() {
+				continue
+			}
+			if source := forbidOverride(gs, forbid); source != nil {
+				result.IgnoredBy = source
+				continue
+			}
+			result.Negated = true
+			result.Reason = NegatedByForbid
+			result.NegatedBy = append(result.NegatedBy, forbid)
+		}
+	}
+	return resolution
+}
+
+// forbidOverride 返回可以无视该禁止卡的角色
+func forbidOverride(gs *GameState, forbid Card) *Character {
+	if gs == nil {
+		return nil
+	}
+	for _, char := range gs.Characters {
+		if !char.IsAlive() || char.Role() == nil {
+			continue
+		}
+		for _, ability := range char.Role().Abilities {
+			override, ok := ability.(ForbidOverrideAbility)
+			if ok && override.IgnoresForbid(gs, char, forbid) {
+				return char
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package models
+
+import "testing"
+
+// passiveAbility 测试用的被动身份能力，不会主动触发
+type passiveAbility struct{}
+
+func (a *passiveAbility) RoleType() RoleType { return "TestRole" }
+func (a *passiveAbility) IsTriggerable(gameState *GameState, target RoleAbilityTarget) (bool, error) {
+	return false, nil
+}
+func (a *passiveAbility) Execute(gameState *GameState, target RoleAbilityTarget) error {
+	return nil
+}
+func (a *passiveAbility) GetTiming() RoleAbilityTiming    { return RoleTimingCardResolve }
+func (a *passiveAbility) GetMandatory() AbilityObligation { return AbilityMandatory }
+
+// ignoreForbidAbility 无视放置在自己身上的禁止卡，与 Cultist 类似
+type ignoreForbidAbility struct{ passiveAbility }
+
+func (a *ignoreForbidAbility) IgnoresForbid(gameState *GameState, source *Character, forbid Card) bool {
+	return forbid.Target() == TargetType(source)
+}
+
+func TestResolveForbids(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(f *boardFixture)
+		placed func(f *boardFixture) []CardPlacement
+		// want 按 placed 的顺序，每张卡牌被无效化的原因，未被无效化时为空
+		want []NegationReason
+		// ignored 无视禁止卡的角色，按 placed 的顺序
+		ignored []CharacterName
+	}{
+		{
+			name: "forbid cancels its matching card type",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, ForbidGoodwillType, 0), Target: f.character("Boy")},
+					{Card: f.card(f.protagonists[0], GoodwillType, 0), Target: f.character("Boy")},
+				}
+			},
+			want: []NegationReason{"", NegatedByForbid},
+		},
+		{
+			name: "forbid ignores other card types",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, ForbidGoodwillType, 0), Target: f.character("Boy")},
+					{Card: f.card(f.protagonists[0], ParanoiaType, 1), Target: f.character("Boy")},
+				}
+			},
+			want: []NegationReason{"", ""},
+		},
+		{
+			name: "forbid ignores other targets",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, ForbidParanoiaType, 0), Target: f.character("Girl")},
+					{Card: f.card(f.protagonists[0], ParanoiaType, 1), Target: f.character("Boy")},
+				}
+			},
+			want: []NegationReason{"", ""},
+		},
+		{
+			name: "two forbids on one target cancel each other",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, ForbidParanoiaType, 0), Target: f.character("Boy")},
+					{Card: f.card(f.protagonists[0], ForbidMovementType, 0), Target: f.character("Boy")},
+					{Card: f.card(f.protagonists[1], ParanoiaType, 0), Target: f.character("Girl")},
+				}
+			},
+			want: []NegationReason{NegatedByForbidPair, NegatedByForbidPair, ""},
+		},
+		{
+			name: "forbid movement cancels every movement card on the target",
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, MovementType, 0), Target: f.character("Boy")},
+					{Card: f.card(f.protagonists[0], ForbidMovementType, 0), Target: f.character("Boy")},
+				}
+			},
+			want: []NegationReason{NegatedByForbid, ""},
+		},
+		{
+			name: "role ability ignores a forbid on itself",
+			setup: func(f *boardFixture) {
+				f.character("Boy").SetRole(&Role{Type: "Cultist", Abilities: []RoleAbility{&ignoreForbidAbility{}}})
+			},
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, IntrigueType, 0), Target: f.character("Boy")},
+					{Card: NewForbidIntrigueCard(f.protagonists[0], false), Target: f.character("Boy")},
+				}
+			},
+			want:    []NegationReason{"", ""},
+			ignored: []CharacterName{"Boy", ""},
+		},
+		{
+			name: "dead role holder cannot ignore a forbid",
+			setup: func(f *boardFixture) {
+				f.character("Doctor").SetRole(&Role{Type: "Cultist", Abilities: []RoleAbility{&ignoreForbidAbility{}}})
+				if _, err := f.gs.KillCharacter(f.character("Doctor"), DeathByEffect, "test"); err != nil {
+					f.t.Fatal(err)
+				}
+			},
+			placed: func(f *boardFixture) []CardPlacement {
+				return []CardPlacement{
+					{Card: f.card(f.mastermind, IntrigueType, 0), Target: f.location(LocationHospital)},
+					{Card: NewForbidIntrigueCard(f.protagonists[0], false), Target: f.location(LocationHospital)},
+				}
+			},
+			want: []NegationReason{NegatedByForbid, ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBoardFixture(t)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			placed := tt.placed(f)
+			f.place(placed...)
+
+			cards := make([]Card, 0, len(placed))
+			for _, placement := range placed {
+				cards = append(cards, placement.Card)
+			}
+			resolution := resolveForbids(f.gs, cards)
+			for i, placement := range placed {
+				result := resolution.Result(placement.Card)
+				if result == nil {
+					t.Fatalf("no result for %s", placement.Card.Id())
+				}
+				if result.Negated != (tt.want[i] != "") || result.Reason != tt.want[i] {
+					t.Errorf("%s: negated = %v (%s), want %q", placement.Card.Id(), result.Negated, result.Reason, tt.want[i])
+				}
+				if result.Negated && len(result.NegatedBy) == 0 {
+					t.Errorf("%s: negated without recording the forbid", placement.Card.Id())
+				}
+				var ignoredBy CharacterName
+				if result.IgnoredBy != nil {
+					ignoredBy = result.IgnoredBy.Name
+				}
+				if tt.ignored != nil && ignoredBy != tt.ignored[i] {
+					t.Errorf("%s: ignored by %q, want %q", placement.Card.Id(), ignoredBy, tt.ignored[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResolveActionCardsSkipsNegatedCards(t *testing.T) {
+	f := newBoardFixture(t)
+	f.place(
+		CardPlacement{Card: f.card(f.mastermind, ForbidGoodwillType, 0), Target: f.character("Boy")},
+		CardPlacement{Card: f.card(f.protagonists[0], GoodwillType, 0), Target: f.character("Boy")},
+		CardPlacement{Card: f.card(f.protagonists[1], GoodwillType, 0), Target: f.character("Girl")},
+	)
+
+	resolution, err := f.gs.Board.ResolveActionCards(f.gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.character("Boy").Goodwill(); got != 0 {
+		t.Errorf("Boy goodwill = %d, want 0", got)
+	}
+	if got := f.character("Girl").Goodwill(); got != 1 {
+		t.Errorf("Girl goodwill = %d, want 1", got)
+	}
+	if negated := resolution.Negated(); len(negated) != 1 || negated[0].Target != TargetType(f.character("Boy")) {
+		t.Errorf("negated = %v, want the goodwill card on Boy", negated)
+	}
+}
@@ -29,18 +29,36 @@ const (
 	RoleTimingAlways RoleAbilityTiming = "Always"
 )
 
+// GoodwillRefusal 身份对好感度能力的拒绝规则
 type GoodwillRefusal string
 
 const (
-	GoodwillRefusalMust      GoodwillRefusal = "Must"      // 不拒绝
+	GoodwillRefusalNone      GoodwillRefusal = ""          // 不拒绝
 	GoodwillRefusalOptional  GoodwillRefusal = "Optional"  // 可以选拒绝
 	GoodwillRefusalMandatory GoodwillRefusal = "Mandatory" // 必须拒绝
 )
 
+// AbilityObligation 身份能力满足条件时是否必须发动
+type AbilityObligation string
+
+const (
+	AbilityMandatory AbilityObligation = "Mandatory" // 必须发动
+	AbilityOptional  AbilityObligation = "Optional"  // 由幕后主使决定是否发动
+)
+
 type Role struct {
 	Type      RoleType
 	Name      string
 	Abilities []RoleAbility
+
+	// GoodwillRefusal 身份对好感度能力的拒绝规则：
+	// Optional 由幕后主使决定是否拒绝，Mandatory 必须拒绝，零值 None 不拒绝
+	GoodwillRefusal GoodwillRefusal
+}
+
+// GoodwillRefusalRule 返回身份的好感度拒绝规则
+func (r *Role) GoodwillRefusalRule() GoodwillRefusal {
+	return r.GoodwillRefusal
 }
 
 type RoleAbilityTarget interface {
@@ -51,7 +69,20 @@ type RoleAbility interface {
 	IsTriggerable(gameState *GameState, target RoleAbilityTarget) (bool, error)
 	Execute(gameState *GameState, target RoleAbilityTarget) error
 	GetTiming() RoleAbilityTiming
-	GetMandatory() GoodwillRefusal
+	GetMandatory() AbilityObligation
+}
+
+// TargetedRoleAbility 需要幕后主使选择目标的能力
+type TargetedRoleAbility interface {
+	RoleAbility
+	// TargetOptions 返回能力所属角色当前可选的目标
+	TargetOptions(gameState *GameState, source *Character) []TargetType
+}
+
+// OncePerLoopRoleAbility 每个循环只能发动一次的能力，使用记录保存在 GameState 中
+type OncePerLoopRoleAbility interface {
+	RoleAbility
+	OncePerLoop() bool
 }
 
 type RolePerson struct {
@@ -75,6 +106,6 @@ func (r *RolePerson) GetTiming() RoleAbilityTiming {
 	return RoleTimingGoodwillUse
 }
 
-func (r *RolePerson) GetMandatory() GoodwillRefusal {
-	return GoodwillRefusalMust
+func (r *RolePerson) GetMandatory() AbilityObligation {
+	return AbilityMandatory
 }
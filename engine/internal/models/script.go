@@ -4,16 +4,22 @@ package models
 type Script struct {
 	// Title 剧本标题
 	Title string
+	// TragedySet 剧本使用的惨剧集 ID
+	TragedySet string
 	// 主要剧情
 	MainPlot *Plot
 	// 子剧情(Basic Tragedy Set 会有两个子剧情)
 	SubPlots []*Plot
 	// 角色列表
 	Characters []*Character
-	// 事件列表
-	Incidents []Incident
+	// 事件日程
+	Incidents []*ScheduledIncident
 	// 循环次数限制
 	MaxLoops int
 	// 每个循环的天数
 	DaysPerLoop int
+	// 地图，为空时使用标准地图
+	Topology *Topology
+	// 剧本的特殊规则说明
+	SpecialRules []string
 }
@@ -0,0 +1,22 @@
+package models
+
+// Seat 表示玩家在游戏中的席位
+type Seat string
+
+const (
+	SeatMastermind  Seat = "Mastermind"  // 幕后主使
+	SeatProtagonist Seat = "Protagonist" // 主角方
+	SeatSpectator   Seat = "Spectator"   // 观战者
+)
+
+// SeatOf 返回玩家所在的席位，非玩家视为观战者
+func SeatOf(player Player) Seat {
+	switch player.(type) {
+	case *Mastermind:
+		return SeatMastermind
+	case *Protagonist:
+		return SeatProtagonist
+	default:
+		return SeatSpectator
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrTimeSpiralClosed 时间螺旋讨论窗口未开启
+var ErrTimeSpiralClosed = errors.New("time spiral is not open")
+
+// Note 主角方在时间螺旋中留在共享笔记板上的笔记或推理
+type Note struct {
+	Loop      int    // 写下笔记时即将开始的循环
+	Author    string // 主角ID
+	Text      string
+	CreatedAt time.Time
+}
+
+// TimeSpiral 循环之间主角方的讨论窗口，笔记板在整局游戏中保留
+type TimeSpiral struct {
+	Duration time.Duration // 讨论时长，为 0 时不限时，等待所有主角准备完毕
+
+	Loop     int             // 当前窗口所属的循环
+	OpenedAt time.Time       // 窗口开启时间，未开启时为零值
+	Ready    map[string]bool // 已准备完毕的主角
+	Notes    []*Note         // 共享笔记板
+}
+
+// NewTimeSpiral 创建指定讨论时长的时间螺旋
+func NewTimeSpiral(duration time.Duration) *TimeSpiral {
+	return &TimeSpiral{Duration: duration, Ready: make(map[string]bool)}
+}
+
+// Open 开启新循环前的讨论窗口
+func (ts *TimeSpiral) Open(loop int, now time.Time) {
+	ts.Loop = loop
+	ts.OpenedAt = now
+	ts.Ready = make(map[string]bool)
+}
+
+// Close 关闭讨论窗口
+func (ts *TimeSpiral) Close() {
+	ts.OpenedAt = time.Time{}
+}
+
+// IsOpen 讨论窗口是否开启
+func (ts *TimeSpiral) IsOpen() bool {
+	return !ts.OpenedAt.IsZero()
+}
+
+// Deadline 返回窗口关闭的时间，不限时返回零值
+func (ts *TimeSpiral) Deadline() time.Time {
+	if !ts.IsOpen() || ts.Duration <= 0 {
+		return time.Time{}
+	}
+	return ts.OpenedAt.Add(ts.Duration)
+}
+
+// Expired 讨论时间是否已经用完
+func (ts *TimeSpiral) Expired(now time.Time) bool {
+	deadline := ts.Deadline()
+	return !deadline.IsZero() && !now.Before(deadline)
+}
+
+// AddNote 在共享笔记板上添加一条笔记
+func (ts *TimeSpiral) AddNote(author, text string, now time.Time) (*Note, error) {
+	if !ts.IsOpen() {
+		return nil, ErrTimeSpiralClosed
+	}
+	if text == "" {
+		return nil, fmt.Errorf("note from %s is empty", author)
+	}
+	note := &Note{Loop: ts.Loop, Author: author, Text: text, CreatedAt: now}
+	ts.Notes = append(ts.Notes, note)
+	return note, nil
+}
+
+// MarkReady 记录一批主角准备完毕，ID 必须都属于 protagonists，任一不合法时不记录
+func (ts *TimeSpiral) MarkReady(protagonists Protagonists, protagonistIDs ...string) error {
+	if !ts.IsOpen() {
+		return ErrTimeSpiralClosed
+	}
+	for _, id := range protagonistIDs {
+		if !slices.ContainsFunc(protagonists, func(p *Protagonist) bool { return p.ID == id }) {
+			return fmt.Errorf("unknown protagonist %q", id)
+		}
+	}
+	for _, id := range protagonistIDs {
+		ts.Ready[id] = true
+	}
+	return nil
+}
+
+// AllReady 是否所有主角都已准备完毕
+func (ts *TimeSpiral) AllReady(protagonists Protagonists) bool {
+	for _, p := range protagonists {
+		if !ts.Ready[p.ID] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeSpiralDeadline(t *testing.T) {
+	opened := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		duration time.Duration
+		open     bool
+		now      time.Time
+		deadline time.Time
+		expired  bool
+	}{
+		{name: "closed window", duration: time.Minute, now: opened.Add(time.Hour)},
+		{name: "unlimited window", open: true, now: opened.Add(time.Hour)},
+		{name: "before the deadline", duration: time.Minute, open: true, now: opened.Add(59 * time.Second), deadline: opened.Add(time.Minute)},
+		{name: "at the deadline", duration: time.Minute, open: true, now: opened.Add(time.Minute), deadline: opened.Add(time.Minute), expired: true},
+		{name: "after the deadline", duration: time.Minute, open: true, now: opened.Add(time.Hour), deadline: opened.Add(time.Minute), expired: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTimeSpiral(tt.duration)
+			if tt.open {
+				ts.Open(2, opened)
+			}
+			if ts.IsOpen() != tt.open {
+				t.Errorf("IsOpen() = %v, want %v", ts.IsOpen(), tt.open)
+			}
+			if got := ts.Deadline(); !got.Equal(tt.deadline) {
+				t.Errorf("Deadline() = %v, want %v", got, tt.deadline)
+			}
+			if got := ts.Expired(tt.now); got != tt.expired {
+				t.Errorf("Expired() = %v, want %v", got, tt.expired)
+			}
+		})
+	}
+}
+
+func TestTimeSpiralMarkReady(t *testing.T) {
+	protagonists := Protagonists{NewProtagonist("P1", true), NewProtagonist("P2", false), NewProtagonist("P3", false)}
+	tests := []struct {
+		name string
+		// closed 标记前关闭窗口
+		closed bool
+		// before 之前已经准备完毕的主角
+		before []string
+		ids    []string
+		// fails 标记是否失败，窗口关闭时失败原因为 ErrTimeSpiralClosed
+		fails bool
+		ready []string
+		all   bool
+	}{
+		{name: "one protagonist", ids: []string{"P2"}, ready: []string{"P2"}},
+		{name: "every protagonist at once", ids: []string{"P1", "P2", "P3"}, ready: []string{"P1", "P2", "P3"}, all: true},
+		{name: "adds to earlier marks", before: []string{"P1"}, ids: []string{"P2", "P3"}, ready: []string{"P1", "P2", "P3"}, all: true},
+		{name: "marking twice is harmless", before: []string{"P1"}, ids: []string{"P1"}, ready: []string{"P1"}},
+		{name: "unknown id marks nobody", ids: []string{"P1", "P9"}, fails: true},
+		{name: "closed window", closed: true, ids: []string{"P1"}, fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTimeSpiral(0)
+			ts.Open(2, time.Now())
+			if err := ts.MarkReady(protagonists, tt.before...); err != nil {
+				t.Fatal(err)
+			}
+			if tt.closed {
+				ts.Close()
+			}
+
+			err := ts.MarkReady(protagonists, tt.ids...)
+			if (err != nil) != tt.fails {
+				t.Fatalf("err = %v, want failure %v", err, tt.fails)
+			}
+			if tt.closed {
+				if !errors.Is(err, ErrTimeSpiralClosed) {
+					t.Errorf("err = %v, want %v", err, ErrTimeSpiralClosed)
+				}
+				return
+			}
+
+			if len(ts.Ready) != len(tt.ready) {
+				t.Errorf("ready = %v, want %v", ts.Ready, tt.ready)
+			}
+			for _, id := range tt.ready {
+				if !ts.Ready[id] {
+					t.Errorf("%s is not ready", id)
+				}
+			}
+			if got := ts.AllReady(protagonists); got != tt.all {
+				t.Errorf("AllReady() = %v, want %v", got, tt.all)
+			}
+		})
+	}
+}
+
+func TestTimeSpiralNotes(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ts := NewTimeSpiral(time.Minute)
+	if _, err := ts.AddNote("P1", "the Doctor is the Brain", now); !errors.Is(err, ErrTimeSpiralClosed) {
+		t.Fatalf("note before opening: err = %v, want %v", err, ErrTimeSpiralClosed)
+	}
+
+	ts.Open(2, now)
+	note, err := ts.AddNote("P1", "the Doctor is the Brain", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Loop != 2 || note.Author != "P1" || !note.CreatedAt.Equal(now) {
+		t.Errorf("note = %+v", note)
+	}
+	if _, err := ts.AddNote("P2", "", now); err == nil {
+		t.Error("empty note should be rejected")
+	}
+
+	// 笔记板在之后的循环中保留，准备状态重新开始
+	if err := ts.MarkReady(Protagonists{NewProtagonist("P1", true)}, "P1"); err != nil {
+		t.Fatal(err)
+	}
+	ts.Close()
+	ts.Open(3, now.Add(time.Hour))
+	if len(ts.Notes) != 1 || len(ts.Ready) != 0 {
+		t.Errorf("after reopening: %d notes, %d ready, want 1 and 0", len(ts.Notes), len(ts.Ready))
+	}
+}
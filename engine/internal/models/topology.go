@@ -0,0 +1,178 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Position 位置在地图网格中的坐标
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Topology 用数据描述的地图结构
+//
+// Grid 按行列出网格中的位置，相邻关系与移动目标都由 Moves 中的移动向量推导，
+// 越过边界时回绕到另一侧；Offboard 中的位置（如 Faraway）不在网格中，不能通过移动卡进出。
+type Topology struct {
+	Name     string                         `json:"name"`
+	Grid     [][]LocationType               `json:"grid"`
+	Offboard []LocationType                 `json:"offboard,omitempty"`
+	Moves    map[MovementDirection]Position `json:"moves,omitempty"`
+}
+
+var (
+	// ErrInvalidTopology 地图定义不合法
+	ErrInvalidTopology = errors.New("invalid board topology")
+	// ErrUnknownLocation 地图中不存在该位置
+	ErrUnknownLocation = errors.New("unknown location")
+	// ErrNoMovement 该位置无法按指定方向移动
+	ErrNoMovement = errors.New("no movement possible")
+)
+
+// standardMoves 标准移动向量：横向、纵向、斜向
+var standardMoves = map[MovementDirection]Position{
+	HorizontalMovement: {X: 1, Y: 0},
+	VerticalMovement:   {X: 0, Y: 1},
+	DiagonalMovement:   {X: 1, Y: 1},
+}
+
+// StandardTopology 返回基础规则的 2x2 地图
+//
+//	医院 | 神社
+//	城市 | 学校
+func StandardTopology() *Topology {
+	return &Topology{
+		Name: "Standard",
+		Grid: [][]LocationType{
+			{LocationHospital, LocationShrine},
+			{LocationCity, LocationSchool},
+		},
+	}
+}
+
+// ParseTopology 从 JSON 解析并校验地图定义
+func ParseTopology(data []byte) (*Topology, error) {
+	topology := &Topology{}
+	if err := json.Unmarshal(data, topology); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTopology, err)
+	}
+	if err := topology.Validate(); err != nil {
+		return nil, err
+	}
+	return topology, nil
+}
+
+// Validate 检查网格为矩形且位置不重复
+func (t *Topology) Validate() error {
+	if len(t.Grid) == 0 || len(t.Grid[0]) == 0 {
+		return fmt.Errorf("%w: empty grid", ErrInvalidTopology)
+	}
+	seen := make(map[LocationType]bool)
+	for y, row := range t.Grid {
+		if len(row) != len(t.Grid[0]) {
+			return fmt.Errorf("%w: row %d has %d columns, expected %d", ErrInvalidTopology, y, len(row), len(t.Grid[0]))
+		}
+		for _, locType := range row {
+			if locType == "" {
+				continue
+			}
+			if seen[locType] {
+				return fmt.Errorf("%w: duplicate location %s", ErrInvalidTopology, locType)
+			}
+			seen[locType] = true
+		}
+	}
+	for _, locType := range t.Offboard {
+		if locType == "" || seen[locType] {
+			return fmt.Errorf("%w: duplicate or empty offboard location %q", ErrInvalidTopology, locType)
+		}
+		seen[locType] = true
+	}
+	for direction := range t.Moves {
+		if _, ok := standardMoves[direction]; !ok {
+			return fmt.Errorf("%w: unknown movement direction %q", ErrInvalidTopology, direction)
+		}
+	}
+	return nil
+}
+
+// LocationTypes 按网格顺序返回所有位置，网格外的位置排在最后
+func (t *Topology) LocationTypes() []LocationType {
+	types := make([]LocationType, 0)
+	for _, row := range t.Grid {
+		for _, locType := range row {
+			if locType != "" {
+				types = append(types, locType)
+			}
+		}
+	}
+	return append(types, t.Offboard...)
+}
+
+// Has 地图中是否存在该位置
+func (t *Topology) Has(locType LocationType) bool {
+	for _, existing := range t.LocationTypes() {
+		if existing == locType {
+			return true
+		}
+	}
+	return false
+}
+
+// PositionOf 返回位置在网格中的坐标，网格外的位置返回 false
+func (t *Topology) PositionOf(locType LocationType) (Position, bool) {
+	for y, row := range t.Grid {
+		for x, existing := range row {
+			if existing == locType {
+				return Position{X: x, Y: y}, true
+			}
+		}
+	}
+	return Position{}, false
+}
+
+// Vector 返回移动方向对应的向量，未自定义时使用标准向量
+func (t *Topology) Vector(direction MovementDirection) (Position, bool) {
+	if vector, ok := t.Moves[direction]; ok {
+		return vector, true
+	}
+	vector, ok := standardMoves[direction]
+	return vector, ok
+}
+
+// Destination 返回从某位置按方向移动后到达的位置
+func (t *Topology) Destination(from LocationType, direction MovementDirection) (LocationType, error) {
+	if !t.Has(from) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownLocation, from)
+	}
+	pos, ok := t.PositionOf(from)
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not on the grid", ErrNoMovement, from)
+	}
+	vector, ok := t.Vector(direction)
+	if !ok {
+		return "", fmt.Errorf("%w: invalid direction %q", ErrNoMovement, direction)
+	}
+
+	height, width := len(t.Grid), len(t.Grid[0])
+	x := ((pos.X+vector.X)%width + width) % width
+	y := ((pos.Y+vector.Y)%height + height) % height
+	to := t.Grid[y][x]
+	if to == "" || to == from {
+		return "", fmt.Errorf("%w: %s %s", ErrNoMovement, from, direction)
+	}
+	return to, nil
+}
+
+// IsAdjacent 两个位置之间是否可以通过一次移动到达
+func (t *Topology) IsAdjacent(a, b LocationType) bool {
+	for direction := range standardMoves {
+		if to, err := t.Destination(a, direction); err == nil && to == b {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrDuplicateContent 同一 ID 的内容重复注册
+	ErrDuplicateContent = errors.New("duplicate content")
+	// ErrUnknownTragedySet 未注册的惨剧集
+	ErrUnknownTragedySet = errors.New("unknown tragedy set")
+)
+
+// RoleFactory 创建新的身份实例，身份能力可能带有每循环的状态，因此每个剧本都需要新的实例
+type RoleFactory func() *Role
+
+// CharacterFactory 创建带有指定身份的角色
+type CharacterFactory func(role *Role) *Character
+
+// IncidentFactory 创建事件
+type IncidentFactory func() Incident
+
+// TragedySet 惨剧集，以稳定的 ID 注册一套规则中的剧情、身份、角色和事件
+//
+// 剧本加载、验证、规则浏览和 AI 都通过 ID 查找内容，不需要引用具体的 Go 类型。
+type TragedySet struct {
+	ID   string
+	Name string
+
+	plots      map[string]*Plot
+	plotOrder  []string
+	roles      map[RoleType]RoleFactory
+	roleOrder  []RoleType
+	characters map[CharacterName]CharacterFactory
+	charOrder  []CharacterName
+	incidents  map[IncidentType]IncidentFactory
+	incOrder   []IncidentType
+}
+
+// NewTragedySet 创建空的惨剧集
+func NewTragedySet(id, name string) *TragedySet {
+	return &TragedySet{
+		ID:         id,
+		Name:       name,
+		plots:      make(map[string]*Plot),
+		roles:      make(map[RoleType]RoleFactory),
+		characters: make(map[CharacterName]CharacterFactory),
+		incidents:  make(map[IncidentType]IncidentFactory),
+	}
+}
+
+// AddPlot 注册剧情
+func (s *TragedySet) AddPlot(plot *Plot) error {
+	if _, ok := s.plots[plot.ID]; ok {
+		return fmt.Errorf("%w: plot %q in %s", ErrDuplicateContent, plot.ID, s.ID)
+	}
+	s.plots[plot.ID] = plot
+	s.plotOrder = append(s.plotOrder, plot.ID)
+	return nil
+}
+
+// AddRole 注册身份
+func (s *TragedySet) AddRole(roleType RoleType, factory RoleFactory) error {
+	if _, ok := s.roles[roleType]; ok {
+		return fmt.Errorf("%w: role %q in %s", ErrDuplicateContent, roleType, s.ID)
+	}
+	s.roles[roleType] = factory
+	s.roleOrder = append(s.roleOrder, roleType)
+	return nil
+}
+
+// AddCharacter 注册角色
+func (s *TragedySet) AddCharacter(name CharacterName, factory CharacterFactory) error {
+	if _, ok := s.characters[name]; ok {
+		return fmt.Errorf("%w: character %q in %s", ErrDuplicateContent, name, s.ID)
+	}
+	s.characters[name] = factory
+	s.charOrder = append(s.charOrder, name)
+	return nil
+}
+
+// AddIncident 注册事件
+func (s *TragedySet) AddIncident(incidentType IncidentType, factory IncidentFactory) error {
+	if _, ok := s.incidents[incidentType]; ok {
+		return fmt.Errorf("%w: incident %q in %s", ErrDuplicateContent, incidentType, s.ID)
+	}
+	s.incidents[incidentType] = factory
+	s.incOrder = append(s.incOrder, incidentType)
+	return nil
+}
+
+// Plot 按 ID 查找剧情
+func (s *TragedySet) Plot(id string) (*Plot, bool) {
+	plot, ok := s.plots[id]
+	return plot, ok
+}
+
+// NewRole 按身份类型创建新的身份实例
+func (s *TragedySet) NewRole(roleType RoleType) (*Role, bool) {
+	factory, ok := s.roles[roleType]
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// NewCharacter 按名称创建带有指定身份的角色
+func (s *TragedySet) NewCharacter(name CharacterName, role *Role) (*Character, bool) {
+	factory, ok := s.characters[name]
+	if !ok {
+		return nil, false
+	}
+	return factory(role), true
+}
+
+// NewIncident 按类型创建事件
+func (s *TragedySet) NewIncident(incidentType IncidentType) (Incident, bool) {
+	factory, ok := s.incidents[incidentType]
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// Plots 按注册顺序返回指定类型的剧情，类型为空时返回所有剧情
+func (s *TragedySet) Plots(plotType PlotType) []*Plot {
+	plots := make([]*Plot, 0, len(s.plotOrder))
+	for _, id := range s.plotOrder {
+		if plot := s.plots[id]; plotType == "" || plot.Type == plotType {
+			plots = append(plots, plot)
+		}
+	}
+	return plots
+}
+
+// Roles 按注册顺序返回允许的身份
+func (s *TragedySet) Roles() []RoleType {
+	return append([]RoleType(nil), s.roleOrder...)
+}
+
+// Characters 按注册顺序返回可用的角色
+func (s *TragedySet) Characters() []CharacterName {
+	return append([]CharacterName(nil), s.charOrder...)
+}
+
+// Incidents 按注册顺序返回允许的事件
+func (s *TragedySet) Incidents() []IncidentType {
+	return append([]IncidentType(nil), s.incOrder...)
+}
+
+// AllowsRole 惨剧集是否包含该身份
+func (s *TragedySet) AllowsRole(roleType RoleType) bool {
+	_, ok := s.roles[roleType]
+	return ok
+}
+
+// AllowsIncident 惨剧集是否包含该事件
+func (s *TragedySet) AllowsIncident(incidentType IncidentType) bool {
+	_, ok := s.incidents[incidentType]
+	return ok
+}
+
+// tragedySets 已注册的惨剧集
+var tragedySets = map[string]*TragedySet{}
+
+// RegisterTragedySet 注册惨剧集，通常在内容包的 init 中调用
+func RegisterTragedySet(set *TragedySet) error {
+	if _, ok := tragedySets[set.ID]; ok {
+		return fmt.Errorf("%w: tragedy set %q", ErrDuplicateContent, set.ID)
+	}
+	tragedySets[set.ID] = set
+	return nil
+}
+
+// LookupTragedySet 按 ID 查找惨剧集
+func LookupTragedySet(id string) (*TragedySet, bool) {
+	set, ok := tragedySets[id]
+	return set, ok
+}
+
+// TragedySets 返回按 ID 排序的所有惨剧集
+func TragedySets() []*TragedySet {
+	list := make([]*TragedySet, 0, len(tragedySets))
+	for _, set := range tragedySets {
+		list = append(list, set)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
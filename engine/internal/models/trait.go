@@ -0,0 +1,75 @@
+package models
+
+// 角色标签，能力和事件按标签筛选角色，例如“同位置的学生”
+const (
+	TagStudent   CharacterTag = "Student"   // 学生
+	TagBoy       CharacterTag = "Boy"       // 少年
+	TagGirl      CharacterTag = "Girl"      // 少女
+	TagAdult     CharacterTag = "Adult"     // 成人
+	TagMan       CharacterTag = "Man"       // 男性
+	TagWoman     CharacterTag = "Woman"     // 女性
+	TagConstruct CharacterTag = "Construct" // 造物
+	TagAnimal    CharacterTag = "Animal"    // 动物
+	TagSister    CharacterTag = "Sister"    // 妹妹
+)
+
+// 角色特征类型
+const (
+	TraitForbiddenArea TraitType = "ForbiddenArea" // 禁行区域，角色不能进入的位置
+)
+
+// ForbiddenAreaEffect 禁行区域特征效果，循环开始时加入角色的禁止位置
+type ForbiddenAreaEffect struct {
+	Locations []LocationType
+}
+
+func (e *ForbiddenAreaEffect) Apply(c *Character, gs *GameState) {
+	for _, location := range e.Locations {
+		if c.CanMoveTo(location) {
+			c.ForbiddenLocations = append(c.ForbiddenLocations, location)
+		}
+	}
+}
+
+func (e *ForbiddenAreaEffect) Remove(c *Character, gs *GameState) {
+	remaining := make([]LocationType, 0, len(c.ForbiddenLocations))
+	for _, location := range c.ForbiddenLocations {
+		if !containsLocation(e.Locations, location) {
+			remaining = append(remaining, location)
+		}
+	}
+	c.ForbiddenLocations = remaining
+}
+
+// ForbiddenArea 创建禁行区域特征
+func ForbiddenArea(locations ...LocationType) CharacterTrait {
+	return CharacterTrait{
+		Type:    TraitForbiddenArea,
+		Effects: []TraitEffect{&ForbiddenAreaEffect{Locations: locations}},
+	}
+}
+
+// HasTag 角色是否带有指定标签
+func (cd *CharacterData) HasTag(tag CharacterTag) bool {
+	return cd.ExistsTag(tag)
+}
+
+// CharactersWithTag 返回带有指定标签的存活角色
+func (gs *GameState) CharactersWithTag(tag CharacterTag) []*Character {
+	characters := make([]*Character, 0)
+	for _, c := range gs.Characters {
+		if c.IsAlive() && c.HasTag(tag) {
+			characters = append(characters, c)
+		}
+	}
+	return characters
+}
+
+func containsLocation(locations []LocationType, location LocationType) bool {
+	for _, l := range locations {
+		if l == location {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,359 @@
+package models
+
+import "sort"
+
+// ViewOptions 控制投影中可见的非公开信息
+type ViewOptions struct {
+	Seat         Seat
+	ShowRoles    bool // 显示所有角色身份，否则只显示已公开的身份
+	ShowCards    bool // 显示其他席位面朝下的行动卡内容
+	ShowCulprits bool // 显示事件当事人
+	ShowPlots    bool // 显示主剧情和子剧情
+}
+
+// MastermindViewOptions 幕后主使可以看到全部信息
+func MastermindViewOptions() ViewOptions {
+	return ViewOptions{Seat: SeatMastermind, ShowRoles: true, ShowCards: true, ShowCulprits: true, ShowPlots: true}
+}
+
+// ProtagonistViewOptions 主角方只能看到公开信息
+func ProtagonistViewOptions() ViewOptions {
+	return ViewOptions{Seat: SeatProtagonist}
+}
+
+// SpectatorViewOptions 观战者默认与主角方看到的信息相同
+func SpectatorViewOptions() ViewOptions {
+	return ViewOptions{Seat: SeatSpectator}
+}
+
+// GameView 某个席位可见的游戏状态投影，面向客户端的接口只返回视图而不返回 GameState
+type GameView struct {
+	Seat        Seat
+	Loop        int
+	MaxLoops    int
+	Day         int
+	DaysPerLoop int
+	GamePhase   GamePhase
+	LoopPhase   LoopPhase
+	DayPhase    DayPhase
+	IsGameOver  bool
+	Winner      string
+
+	Protagonists []ProtagonistView
+	Characters   []CharacterView
+	Locations    []LocationView
+	Cards        []CardView             // 游戏板上的行动卡
+	Incidents    []IncidentView         // 剧本中安排的事件
+	Occurred     []OccurredIncidentView // 所有循环中已经发生的事件
+	MainPlot     *PlotView              // 不可见时为空
+	SubPlots     []PlotView             // 不可见时为空
+}
+
+// ProtagonistView 主角牌组的公开信息
+type ProtagonistView struct {
+	ID         string
+	Controller string
+	IsLeader   bool
+	HandCards  int
+}
+
+// CharacterView 角色的可见状态，身份不可见时 Role 为空
+type CharacterView struct {
+	Name          CharacterName
+	Tags          []CharacterTag
+	Location      LocationType
+	IsAlive       bool
+	Goodwill      int
+	GoodwillLimit int
+	Paranoia      int
+	ParanoiaLimit int
+	Intrigue      int
+	Role          RoleType
+	RoleRevealed  bool // 身份是否已对所有人公开
+}
+
+// LocationView 位置的可见状态
+type LocationView struct {
+	Location   LocationType
+	Intrigue   int
+	Characters []CharacterName
+}
+
+// CardView 游戏板上的一张行动卡，内容不可见时 Hidden 为 true 且只保留所有者和目标
+type CardView struct {
+	Owner    Seat
+	OwnerID  string
+	Target   string
+	FaceDown bool
+	Hidden   bool
+	ID       string
+	Type     CardType
+}
+
+// IncidentView 剧本中安排的事件，当事人不可见时 Culprit 为空
+type IncidentView struct {
+	Day      int
+	Type     IncidentType
+	Culprit  CharacterName
+	Occurred bool // 本循环是否已经发生
+}
+
+// OccurredIncidentView 已经发生的事件，事件是否发生对所有席位公开
+type OccurredIncidentView struct {
+	Loop int
+	Day  int
+	Type IncidentType
+}
+
+// PlotView 剧情的可见信息
+type PlotView struct {
+	Name        string
+	Type        PlotType
+	Description string
+}
+
+// RevealRole 向所有玩家公开角色身份，公开后在之后的循环中保持公开
+func (gs *GameState) RevealRole(name CharacterName) {
+	if gs.RevealedRoles == nil {
+		gs.RevealedRoles = make(map[CharacterName]bool)
+	}
+	gs.RevealedRoles[name] = true
+}
+
+// RoleRevealed 角色身份是否已经公开
+func (gs *GameState) RoleRevealed(name CharacterName) bool {
+	return gs.RevealedRoles[name]
+}
+
+// View 按可见性选项投影游戏状态，游戏结束后所有身份和剧情都会公开
+func (gs *GameState) View(opts ViewOptions) *GameView {
+	view := &GameView{
+		Seat:       opts.Seat,
+		Loop:       gs.CurrentLoop,
+		Day:        gs.CurrentDay,
+		GamePhase:  gs.CurrentGamePhase,
+		LoopPhase:  gs.CurrentLoopPhase,
+		DayPhase:   gs.CurrentDayPhase,
+		IsGameOver: gs.IsGameOver,
+		Winner:     gs.WinnerType,
+	}
+	if gs.IsGameOver {
+		opts.ShowRoles = true
+		opts.ShowPlots = true
+	}
+
+	if script := gs.Script; script != nil {
+		view.MaxLoops = script.MaxLoops
+		view.DaysPerLoop = script.DaysPerLoop
+		if opts.ShowPlots && script.MainPlot != nil {
+			view.MainPlot = plotView(script.MainPlot)
+		}
+		if opts.ShowPlots {
+			for _, plot := range script.SubPlots {
+				view.SubPlots = append(view.SubPlots, *plotView(plot))
+			}
+		}
+	}
+
+	for _, p := range gs.Protagonists {
+		view.Protagonists = append(view.Protagonists, ProtagonistView{
+			ID:         p.ID,
+			Controller: p.Controller,
+			IsLeader:   p.IsLeader,
+			HandCards:  len(p.HandCards),
+		})
+	}
+
+	for _, c := range gs.Characters {
+		cv := CharacterView{
+			Name:          c.Name,
+			Tags:          c.Tags,
+			Location:      c.Location(),
+			IsAlive:       c.IsAlive(),
+			Goodwill:      c.Goodwill(),
+			GoodwillLimit: c.GoodwillLimit,
+			Paranoia:      c.Paranoia(),
+			ParanoiaLimit: c.ParanoiaLimit,
+			Intrigue:      c.Intrigue(),
+			RoleRevealed:  gs.RoleRevealed(c.Name),
+		}
+		if (opts.ShowRoles || cv.RoleRevealed) && c.Role() != nil {
+			cv.Role = c.Role().Type
+		}
+		view.Characters = append(view.Characters, cv)
+	}
+
+	if gs.Board != nil {
+		for _, locType := range gs.Board.Locations() {
+			loc := gs.Board.locations[locType]
+			if loc == nil {
+				continue
+			}
+			lv := LocationView{Location: locType, Intrigue: loc.Intrigue()}
+			for name := range loc.Characters {
+				lv.Characters = append(lv.Characters, name)
+			}
+			sort.Slice(lv.Characters, func(i, j int) bool { return lv.Characters[i] < lv.Characters[j] })
+			view.Locations = append(view.Locations, lv)
+		}
+		for _, card := range gs.Board.actionCards {
+			view.Cards = append(view.Cards, cardView(card, opts))
+		}
+	}
+
+	for _, scheduled := range gs.Incidents {
+		iv := IncidentView{
+			Day:      scheduled.Day,
+			Type:     scheduled.Type(),
+			Occurred: gs.incidentOccurredOn(gs.CurrentLoop, scheduled.Day, scheduled.Type()),
+		}
+		if opts.ShowCulprits {
+			iv.Culprit = scheduled.Culprit
+		}
+		view.Incidents = append(view.Incidents, iv)
+	}
+
+	keys := make([]IncidentKey, 0, len(gs.IncidentsOccurred))
+	for key := range gs.IncidentsOccurred {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Loop != keys[j].Loop {
+			return keys[i].Loop < keys[j].Loop
+		}
+		return keys[i].Day < keys[j].Day
+	})
+	for _, key := range keys {
+		for _, incidentType := range gs.IncidentsOccurred[key] {
+			view.Occurred = append(view.Occurred, OccurredIncidentView{Loop: key.Loop, Day: key.Day, Type: incidentType})
+		}
+	}
+	return view
+}
+
+// Character 按名称查找视图中的角色，找不到时返回空
+func (v *GameView) Character(name CharacterName) *CharacterView {
+	for i := range v.Characters {
+		if v.Characters[i].Name == name {
+			return &v.Characters[i]
+		}
+	}
+	return nil
+}
+
+// Location 查找视图中的位置，找不到时返回空
+func (v *GameView) Location(locationType LocationType) *LocationView {
+	for i := range v.Locations {
+		if v.Locations[i].Location == locationType {
+			return &v.Locations[i]
+		}
+	}
+	return nil
+}
+
+// incidentOccurredOn 事件是否在指定循环的指定日期发生过
+func (gs *GameState) incidentOccurredOn(loop, day int, incidentType IncidentType) bool {
+	for _, t := range gs.IncidentsOccurred[IncidentKey{Loop: loop, Day: day}] {
+		if t == incidentType {
+			return true
+		}
+	}
+	return false
+}
+
+func plotView(plot *Plot) *PlotView {
+	return &PlotView{Name: plot.Name, Type: plot.Type, Description: plot.Description}
+}
+
+// cardView 面朝下的卡牌只有所有者席位可以看到内容
+func cardView(card Card, opts ViewOptions) CardView {
+	return redactCard(fullCardView(card), opts)
+}
+
+// fullCardView 返回包含卡牌内容的视图
+func fullCardView(card Card) CardView {
+	cv := CardView{
+		Owner:    SeatOf(card.Owner()),
+		FaceDown: card.State().faceDown,
+		Target:   TargetName(card.Target()),
+		ID:       card.Id(),
+		Type:     card.Type(),
+	}
+	if p, ok := card.Owner().(*Protagonist); ok {
+		cv.OwnerID = p.ID
+	}
+	return cv
+}
+
+// fullCardViews 返回多张卡牌包含内容的视图
+func fullCardViews(cards []Card) []CardView {
+	views := make([]CardView, 0, len(cards))
+	for _, card := range cards {
+		views = append(views, fullCardView(card))
+	}
+	return views
+}
+
+// redactCard 隐藏 opts 看不到的面朝下卡牌内容
+func redactCard(cv CardView, opts ViewOptions) CardView {
+	if cv.FaceDown && !opts.ShowCards && cv.Owner != opts.Seat {
+		cv.Hidden = true
+		cv.ID = ""
+		cv.Type = ""
+	}
+	return cv
+}
+
+// TargetName 返回目标的名称：角色名或位置
+func TargetName(target TargetType) string {
+	switch t := target.(type) {
+	case *Character:
+		return string(t.Name)
+	case *Location:
+		return string(t.LocationType)
+	default:
+		return ""
+	}
+}
+
+// TargetRef 按名称引用的目标，角色和位置只填写其一
+//
+// 发给主角方的决策请求使用 TargetRef 代替角色指针，避免通过指针读取身份等隐藏信息。
+type TargetRef struct {
+	Character CharacterName `json:",omitempty"`
+	Location  LocationType  `json:",omitempty"`
+}
+
+// RefOf 返回目标的名称引用
+func RefOf(target TargetType) TargetRef {
+	switch t := target.(type) {
+	case *Character:
+		return TargetRef{Character: t.Name}
+	case *Location:
+		return TargetRef{Location: t.LocationType}
+	default:
+		return TargetRef{}
+	}
+}
+
+func (r TargetRef) String() string {
+	if r.Character != "" {
+		return string(r.Character)
+	}
+	return string(r.Location)
+}
+
+// ResolveTarget 按名称引用查找目标，找不到时返回 nil
+func (gs *GameState) ResolveTarget(ref TargetRef) TargetType {
+	switch {
+	case ref.Character != "":
+		if char := gs.Character(ref.Character); char != nil {
+			return char
+		}
+	case ref.Location != "" && gs.Board != nil:
+		if loc := gs.Board.GetLocation(ref.Location); loc != nil {
+			return loc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package scripts
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"tragedy-looper/engine/internal/models"
+)
+
+// ErrCannotGenerate 惨剧集的内容不足以生成合法的剧本
+var ErrCannotGenerate = errors.New("cannot generate script")
+
+// Difficulty 生成剧本的难度目标
+type Difficulty string
+
+const (
+	DifficultyEasy   Difficulty = "easy"   // 循环多、天数少、事件少
+	DifficultyNormal Difficulty = "normal" // 普通难度
+	DifficultyHard   Difficulty = "hard"   // 循环少、天数多、事件多
+)
+
+// difficultyProfile 难度对应的剧本规模
+type difficultyProfile struct {
+	maxLoops    int
+	daysPerLoop int
+	incidents   int
+	characters  int // 角色数量，少于身份数量时按身份数量
+}
+
+var difficultyProfiles = map[Difficulty]difficultyProfile{
+	DifficultyEasy:   {maxLoops: 4, daysPerLoop: 4, incidents: 2, characters: 6},
+	DifficultyNormal: {maxLoops: 3, daysPerLoop: 5, incidents: 3, characters: 7},
+	DifficultyHard:   {maxLoops: 2, daysPerLoop: 6, incidents: 4, characters: 8},
+}
+
+// GenerateOptions 随机生成剧本的参数
+type GenerateOptions struct {
+	Seed       uint64     // 相同的种子和参数生成相同的剧本
+	Difficulty Difficulty // 为空时为普通难度
+	SubPlots   int        // 子剧情数量，为 0 时为 1
+}
+
+// Generate 从惨剧集中随机生成合法的剧本
+//
+// 随机选择主剧情和子剧情，按剧情的身份数量范围把身份分配给随机角色，
+// 再安排当事人为剧本角色的事件。生成的剧本经过与剧本文件相同的解析和验证。
+func Generate(set *models.TragedySet, opts GenerateOptions) (*models.Script, error) {
+	file, err := GenerateFile(set, opts)
+	if err != nil {
+		return nil, err
+	}
+	return Build(file, set)
+}
+
+// GenerateFile 随机生成剧本文件，可以直接保存或交给 Build 创建剧本
+func GenerateFile(set *models.TragedySet, opts GenerateOptions) (*File, error) {
+	if opts.Difficulty == "" {
+		opts.Difficulty = DifficultyNormal
+	}
+	profile, ok := difficultyProfiles[opts.Difficulty]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown difficulty %q", ErrCannotGenerate, opts.Difficulty)
+	}
+	if opts.SubPlots == 0 {
+		opts.SubPlots = 1
+	}
+
+	g := &generator{set: set, rng: rand.New(rand.NewPCG(opts.Seed, opts.Seed))}
+	file := &File{
+		Title:       fmt.Sprintf("%s Random #%d (%s)", set.Name, opts.Seed, opts.Difficulty),
+		TragedySet:  set.ID,
+		MaxLoops:    profile.maxLoops,
+		DaysPerLoop: profile.daysPerLoop,
+	}
+
+	plots, err := g.plots(opts.SubPlots)
+	if err != nil {
+		return nil, err
+	}
+	file.MainPlot = plots[0].ID
+	for _, plot := range plots[1:] {
+		file.SubPlots = append(file.SubPlots, plot.ID)
+	}
+
+	if file.Characters, err = g.characters(plots, profile.characters); err != nil {
+		return nil, err
+	}
+	if file.Incidents, err = g.incidents(file.Characters, profile); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// generator 生成剧本时的状态
+type generator struct {
+	set *models.TragedySet
+	rng *rand.Rand
+}
+
+// plots 选择一个主剧情和指定数量的不同子剧情，主剧情在第一位
+func (g *generator) plots(subPlots int) ([]*models.Plot, error) {
+	mains := g.set.Plots(models.MainPlot)
+	if len(mains) == 0 {
+		return nil, fmt.Errorf("%w: %s has no main plot", ErrCannotGenerate, g.set.ID)
+	}
+	subs := g.set.Plots(models.SubPlot)
+	if len(subs) < subPlots {
+		return nil, fmt.Errorf("%w: %s has %d subplots, %d requested", ErrCannotGenerate, g.set.ID, len(subs), subPlots)
+	}
+	g.rng.Shuffle(len(subs), func(i, j int) { subs[i], subs[j] = subs[j], subs[i] })
+	plots := []*models.Plot{mains[g.rng.IntN(len(mains))]}
+	return append(plots, subs[:subPlots]...), nil
+}
+
+// characters 在每个剧情的身份数量范围内随机决定数量，再分配给随机角色，其余角色为普通人
+func (g *generator) characters(plots []*models.Plot, want int) ([]CharacterEntry, error) {
+	var roles []models.RoleType
+	for _, plot := range plots {
+		for _, roleType := range plot.Roles {
+			r := plot.RoleRanges[roleType]
+			count := r.Min + g.rng.IntN(r.Max-r.Min+1)
+			for i := 0; i < count; i++ {
+				roles = append(roles, roleType)
+			}
+		}
+	}
+
+	roster := g.set.Characters()
+	if len(roles) > len(roster) {
+		return nil, fmt.Errorf("%w: %d roles but only %d characters in %s", ErrCannotGenerate, len(roles), len(roster), g.set.ID)
+	}
+	want = max(want, len(roles))
+	want = min(want, len(roster))
+	g.rng.Shuffle(len(roster), func(i, j int) { roster[i], roster[j] = roster[j], roster[i] })
+	g.rng.Shuffle(len(roles), func(i, j int) { roles[i], roles[j] = roles[j], roles[i] })
+
+	characters := make([]CharacterEntry, want)
+	for i, name := range roster[:want] {
+		characters[i] = CharacterEntry{Name: string(name)}
+		if i < len(roles) {
+			characters[i].Role = string(roles[i])
+		}
+	}
+	// 身份已经随机分配，按名称排序避免身份角色总是排在前面
+	sort.Slice(characters, func(i, j int) bool { return characters[i].Name < characters[j].Name })
+	return characters, nil
+}
+
+// incidents 在不同的日期安排随机事件，当事人从剧本角色中随机选择
+//
+// 事件从惨剧集注册的事件中选择，惨剧集只注册已经实现效果的事件。
+func (g *generator) incidents(characters []CharacterEntry, profile difficultyProfile) ([]IncidentEntry, error) {
+	types := g.set.Incidents()
+	if len(types) == 0 {
+		return nil, fmt.Errorf("%w: %s has no incidents", ErrCannotGenerate, g.set.ID)
+	}
+	days := g.rng.Perm(profile.daysPerLoop)[:min(profile.incidents, profile.daysPerLoop)]
+	sort.Ints(days)
+
+	incidents := make([]IncidentEntry, 0, len(days))
+	for _, day := range days {
+		incidents = append(incidents, IncidentEntry{
+			Type:    string(types[g.rng.IntN(len(types))]),
+			Day:     day + 1,
+			Culprit: characters[g.rng.IntN(len(characters))].Name,
+		})
+	}
+	return incidents, nil
+}
+
+// Difficulties 返回可用的难度
+func Difficulties() []Difficulty {
+	return []Difficulty{DifficultyEasy, DifficultyNormal, DifficultyHard}
+}
@@ -0,0 +1,387 @@
+package scripts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"tragedy-looper/engine/internal/models"
+	"tragedy-looper/engine/internal/validate"
+)
+
+var (
+	// ErrUnknownContent 剧本引用了未注册的内容
+	ErrUnknownContent = errors.New("unknown content")
+	// ErrInvalidScript 剧本内容不合法
+	ErrInvalidScript = errors.New("invalid script")
+	// ErrTragedySetMismatch 剧本的惨剧集与加载时提供的惨剧集不同
+	ErrTragedySetMismatch = errors.New("tragedy set mismatch")
+)
+
+// Content 剧本文件中按 ID 引用的已注册内容，*models.TragedySet 实现了该接口
+type Content interface {
+	// Plot 按 ID 查找剧情
+	Plot(id string) (*models.Plot, bool)
+	// NewRole 按身份类型创建新的身份实例
+	NewRole(roleType models.RoleType) (*models.Role, bool)
+	// NewCharacter 按名称创建带有指定身份的角色
+	NewCharacter(name models.CharacterName, role *models.Role) (*models.Character, bool)
+	// NewIncident 按类型创建事件
+	NewIncident(incidentType models.IncidentType) (models.Incident, bool)
+}
+
+// File 剧本文件格式
+type File struct {
+	Title        string           `json:"title"`
+	TragedySet   string           `json:"tragedySet"`
+	MainPlot     string           `json:"mainPlot"`
+	SubPlots     []string         `json:"subPlots"`
+	Characters   []CharacterEntry `json:"characters"`
+	Incidents    []IncidentEntry  `json:"incidents"`
+	MaxLoops     int              `json:"maxLoops"`
+	DaysPerLoop  int              `json:"daysPerLoop"`
+	SpecialRules []string         `json:"specialRules,omitempty"`
+}
+
+// CharacterEntry 剧本中的角色及其身份，身份为空时为普通人
+type CharacterEntry struct {
+	Name string `json:"name"`
+	Role string `json:"role,omitempty"`
+}
+
+// IncidentEntry 剧本中安排的事件
+type IncidentEntry struct {
+	Type    string `json:"type"`
+	Day     int    `json:"day"`
+	Culprit string `json:"culprit"`
+}
+
+// FieldError 剧本文件中某个字段的错误
+type FieldError struct {
+	Line  int    // 字段所在行，从 1 开始，未知时为 0
+	Field string // 字段路径，如 characters[2].role
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	switch {
+	case e.Field == "":
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	case e.Line == 0:
+		return fmt.Sprintf("%s: %v", e.Field, e.Err)
+	default:
+		return fmt.Sprintf("line %d: %s: %v", e.Line, e.Field, e.Err)
+	}
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// LoadError 加载剧本时发现的所有错误
+type LoadError struct {
+	Errors []*FieldError
+}
+
+func (e *LoadError) Error() string {
+	lines := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *LoadError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// LoadFile 从文件加载剧本
+func LoadFile(path string, content Content) (*models.Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, content)
+}
+
+// Load 从 reader 加载剧本
+func Load(r io.Reader, content Content) (*models.Script, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, content)
+}
+
+// Parse 解析 JSON 格式的剧本并解析其中引用的内容，错误为 *LoadError
+//
+// content 为空时使用剧本 tragedySet 字段指定的已注册惨剧集，
+// content 为惨剧集时必须与 tragedySet 字段一致。
+func Parse(data []byte, content Content) (*models.Script, error) {
+	var file File
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, &LoadError{Errors: []*FieldError{decodeError(data, dec, err)}}
+	}
+
+	l := &loader{content: content, lines: indexLines(data)}
+	return l.load(&file)
+}
+
+// Build 解析已解码的剧本文件中引用的内容并验证剧本，错误为 *LoadError
+//
+// content 为空时使用剧本 tragedySet 字段指定的已注册惨剧集，
+// content 为惨剧集时必须与 tragedySet 字段一致。
+func Build(file *File, content Content) (*models.Script, error) {
+	l := &loader{content: content}
+	return l.load(file)
+}
+
+// loader 解析剧本文件时的状态
+type loader struct {
+	content Content
+	lines   map[string]int
+	errs    []*FieldError
+}
+
+// load 解析剧本中引用的内容，全部成功后再按规则验证剧本
+func (l *loader) load(file *File) (*models.Script, error) {
+	if l.content == nil {
+		set, ok := models.LookupTragedySet(file.TragedySet)
+		if !ok {
+			l.fail("tragedySet", fmt.Errorf("%w: %q", models.ErrUnknownTragedySet, file.TragedySet))
+			return nil, &LoadError{Errors: l.errs}
+		}
+		l.content = set
+	} else if set, ok := l.content.(*models.TragedySet); ok && file.TragedySet != set.ID {
+		l.fail("tragedySet", fmt.Errorf("%w: script uses %q, loading with %q", ErrTragedySetMismatch, file.TragedySet, set.ID))
+		return nil, &LoadError{Errors: l.errs}
+	}
+	script := l.build(file)
+	if len(l.errs) > 0 {
+		return nil, &LoadError{Errors: l.errs}
+	}
+	for _, v := range validate.Script(script) {
+		l.fail(v.Field, fmt.Errorf("%w: %w", ErrInvalidScript, v.Err))
+	}
+	if len(l.errs) > 0 {
+		return nil, &LoadError{Errors: l.errs}
+	}
+	return script, nil
+}
+
+// fail 记录字段错误
+func (l *loader) fail(field string, err error) {
+	l.errs = append(l.errs, &FieldError{Line: l.line(field), Field: field, Err: err})
+}
+
+// check 记录验证失败的字段
+func (l *loader) check(field string, result validate.ValidationResult) {
+	if !result.Valid {
+		l.fail(field, fmt.Errorf("%w: %v", ErrInvalidScript, result.Error))
+	}
+}
+
+// line 返回字段所在行，字段不存在时使用最近的上级字段
+func (l *loader) line(field string) int {
+	for field != "" {
+		if line, ok := l.lines[field]; ok {
+			return line
+		}
+		i := strings.LastIndexAny(field, ".[")
+		if i < 0 {
+			break
+		}
+		field = field[:i]
+	}
+	return l.lines[""]
+}
+
+func (l *loader) build(file *File) *models.Script {
+	script := &models.Script{
+		Title:        file.Title,
+		TragedySet:   file.TragedySet,
+		MaxLoops:     file.MaxLoops,
+		DaysPerLoop:  file.DaysPerLoop,
+		SpecialRules: file.SpecialRules,
+	}
+	l.check("title", validate.StringRequired("title", file.Title))
+
+	script.MainPlot = l.plot("mainPlot", file.MainPlot, models.MainPlot)
+	seenPlots := make(map[string]bool)
+	for i, id := range file.SubPlots {
+		field := fmt.Sprintf("subPlots[%d]", i)
+		if seenPlots[id] {
+			l.fail(field, fmt.Errorf("%w: duplicate subplot %q", ErrInvalidScript, id))
+			continue
+		}
+		seenPlots[id] = true
+		if plot := l.plot(field, id, models.SubPlot); plot != nil {
+			script.SubPlots = append(script.SubPlots, plot)
+		}
+	}
+
+	names := make(map[models.CharacterName]bool)
+	for i, entry := range file.Characters {
+		if character := l.character(i, entry, names); character != nil {
+			script.Characters = append(script.Characters, character)
+		}
+	}
+
+	for i, entry := range file.Incidents {
+		if incident := l.incident(i, entry); incident != nil {
+			script.Incidents = append(script.Incidents, incident)
+		}
+	}
+	return script
+}
+
+// plot 按 ID 查找剧情并检查剧情类型
+func (l *loader) plot(field, id string, plotType models.PlotType) *models.Plot {
+	if id == "" {
+		l.fail(field, fmt.Errorf("%w: plot is required", ErrInvalidScript))
+		return nil
+	}
+	plot, ok := l.content.Plot(id)
+	if !ok {
+		l.fail(field, fmt.Errorf("%w: plot %q", ErrUnknownContent, id))
+		return nil
+	}
+	if plot.Type != plotType {
+		l.fail(field, fmt.Errorf("%w: plot %q is a %s, not a %s", ErrInvalidScript, id, plot.Type, plotType))
+		return nil
+	}
+	return plot
+}
+
+// character 创建带有身份的角色，角色名不能重复
+func (l *loader) character(i int, entry CharacterEntry, names map[models.CharacterName]bool) *models.Character {
+	field := fmt.Sprintf("characters[%d]", i)
+	name := models.CharacterName(entry.Name)
+	if name == "" {
+		l.fail(field+".name", fmt.Errorf("%w: character name is required", ErrInvalidScript))
+		return nil
+	}
+	if names[name] {
+		l.fail(field+".name", fmt.Errorf("%w: duplicate character %q", ErrInvalidScript, name))
+		return nil
+	}
+	names[name] = true
+
+	roleType := models.RoleType(entry.Role)
+	if roleType == "" {
+		roleType = models.RolePersonType
+	}
+	role, ok := l.content.NewRole(roleType)
+	if !ok {
+		l.fail(field+".role", fmt.Errorf("%w: role %q", ErrUnknownContent, entry.Role))
+		return nil
+	}
+	character, ok := l.content.NewCharacter(name, role)
+	if !ok {
+		l.fail(field+".name", fmt.Errorf("%w: character %q", ErrUnknownContent, name))
+		return nil
+	}
+	return character
+}
+
+// incident 创建事件日程，日期和当事人由 validate.Script 检查
+func (l *loader) incident(i int, entry IncidentEntry) *models.ScheduledIncident {
+	incident, ok := l.content.NewIncident(models.IncidentType(entry.Type))
+	if !ok {
+		l.fail(fmt.Sprintf("incidents[%d].type", i), fmt.Errorf("%w: incident %q", ErrUnknownContent, entry.Type))
+		return nil
+	}
+	return &models.ScheduledIncident{
+		Incident: incident,
+		Day:      entry.Day,
+		Culprit:  models.CharacterName(entry.Culprit),
+	}
+}
+
+// decodeError 将 JSON 解码错误转换为带行号的字段错误
+func decodeError(data []byte, dec *json.Decoder, err error) *FieldError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &FieldError{Line: lineAt(data, syntaxErr.Offset), Err: err}
+	case errors.As(err, &typeErr):
+		return &FieldError{Line: lineAt(data, typeErr.Offset), Field: typeErr.Field, Err: err}
+	case errors.Is(err, io.EOF):
+		return &FieldError{Line: 1, Err: fmt.Errorf("%w: empty script", ErrInvalidScript)}
+	default:
+		return &FieldError{Line: lineAt(data, dec.InputOffset()), Err: err}
+	}
+}
+
+// indexLines 记录每个字段值所在的行，字段路径形如 characters[2].role，根对象为空路径
+func indexLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	lines[""] = lineAt(data, skipSpace(data, 0))
+	_ = indexValue(data, dec, "", lines)
+	return lines
+}
+
+func indexValue(data []byte, dec *json.Decoder, path string, lines map[string]int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			field := fmt.Sprintf("%v", key)
+			if path != "" {
+				field = path + "." + field
+			}
+			lines[field] = lineAt(data, dec.InputOffset())
+			if err = indexValue(data, dec, field, lines); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; dec.More(); i++ {
+			field := fmt.Sprintf("%s[%d]", path, i)
+			lines[field] = lineAt(data, skipSpace(data, dec.InputOffset()))
+			if err = indexValue(data, dec, field, lines); err != nil {
+				return err
+			}
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// skipSpace 跳过空白和分隔符，返回下一个值的偏移
+func skipSpace(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// lineAt 返回偏移所在的行号
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
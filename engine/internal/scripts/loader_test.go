@@ -0,0 +1,163 @@
+package scripts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"tragedy-looper/engine/cmd/first_steps"
+	"tragedy-looper/engine/internal/models"
+	"tragedy-looper/engine/internal/validate"
+)
+
+// firstSteps1 First Steps 第一个剧本，测试用例在此基础上替换字段
+const firstSteps1 = `{
+  "title": "First Steps 1",
+  "tragedySet": "first_steps",
+  "mainPlot": "murder_plan",
+  "subPlots": ["shadow_of_the_ripper"],
+  "maxLoops": 3,
+  "daysPerLoop": 3,
+  "characters": [
+    {"name": "BoyStudent", "role": "RolePerson"},
+    {"name": "GirlStudent", "role": "KeyPerson"},
+    {"name": "ShrineMaiden", "role": "SerialKiller"},
+    {"name": "PoliceOfficer", "role": "ConspiracyTheorist"},
+    {"name": "OfficeWorker", "role": "Killer"},
+    {"name": "Doctor", "role": "Brain"}
+  ],
+  "incidents": [
+    {"type": "MurderIncident", "day": 2, "culprit": "ShrineMaiden"},
+    {"type": "SuicideIncident", "day": 3, "culprit": "BoyStudent"}
+  ],
+  "specialRules": []
+}`
+
+// fieldWant 期望的字段错误
+type fieldWant struct {
+	field string
+	line  int
+	err   error
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		// replace 依次替换 firstSteps1 中的文本，成对出现
+		replace []string
+		content Content
+		want    []fieldWant
+	}{
+		{name: "valid script"},
+		{name: "valid script with its tragedy set", content: first_steps.Set},
+		{
+			name:    "unknown tragedy set",
+			replace: []string{`"tragedySet": "first_steps"`, `"tragedySet": "second_steps"`},
+			want:    []fieldWant{{"tragedySet", 3, models.ErrUnknownTragedySet}},
+		},
+		{
+			name:    "different tragedy set",
+			content: models.NewTragedySet("other", "Other"),
+			want:    []fieldWant{{"tragedySet", 3, ErrTragedySetMismatch}},
+		},
+		{
+			name:    "unknown main plot",
+			replace: []string{`"murder_plan"`, `"heist"`},
+			want:    []fieldWant{{"mainPlot", 4, ErrUnknownContent}},
+		},
+		{
+			name:    "subplot as main plot",
+			replace: []string{`"mainPlot": "murder_plan"`, `"mainPlot": "shadow_of_the_ripper"`},
+			want:    []fieldWant{{"mainPlot", 4, ErrInvalidScript}},
+		},
+		{
+			name:    "every unknown role is reported",
+			replace: []string{`"SerialKiller"`, `"Vampire"`, `"Brain"`, `"Genius"`},
+			want: []fieldWant{
+				{"characters[2].role", 11, ErrUnknownContent},
+				{"characters[5].role", 14, ErrUnknownContent},
+			},
+		},
+		{
+			name:    "unknown character",
+			replace: []string{`"name": "Doctor"`, `"name": "Detective"`},
+			want:    []fieldWant{{"characters[5].name", 14, ErrUnknownContent}},
+		},
+		{
+			name:    "duplicate character",
+			replace: []string{`"name": "Doctor"`, `"name": "BoyStudent"`},
+			want:    []fieldWant{{"characters[5].name", 14, ErrInvalidScript}},
+		},
+		{
+			name:    "unknown incident",
+			replace: []string{`"MurderIncident"`, `"Earthquake"`},
+			want:    []fieldWant{{"incidents[0].type", 17, ErrUnknownContent}},
+		},
+		{
+			name:    "validation errors keep their field",
+			replace: []string{`"day": 3`, `"day": 4`},
+			want:    []fieldWant{{"incidents[1].day", 18, validate.ErrInvalidIncident}},
+		},
+		{
+			name:    "wrong value type",
+			replace: []string{`"maxLoops": 3`, `"maxLoops": "3"`},
+			want:    []fieldWant{{"maxLoops", 6, nil}},
+		},
+		{
+			name:    "syntax error",
+			replace: []string{`"daysPerLoop": 3,`, `"daysPerLoop": 3`},
+			want:    []fieldWant{{"", 8, nil}},
+		},
+		{
+			name:    "empty script",
+			replace: []string{firstSteps1, " "},
+			want:    []fieldWant{{"", 1, ErrInvalidScript}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := strings.NewReplacer(tt.replace...).Replace(firstSteps1)
+			script, err := Parse([]byte(data), tt.content)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if script.Title != "First Steps 1" || script.MainPlot.ID != "murder_plan" ||
+					len(script.SubPlots) != 1 || len(script.Characters) != 6 || len(script.Incidents) != 2 {
+					t.Errorf("script = %+v", script)
+				}
+				return
+			}
+
+			var loadErr *LoadError
+			if !errors.As(err, &loadErr) {
+				t.Fatalf("err = %v, want a *LoadError", err)
+			}
+			if len(loadErr.Errors) != len(tt.want) {
+				t.Fatalf("got %d errors, want %d:\n%v", len(loadErr.Errors), len(tt.want), err)
+			}
+			for i, want := range tt.want {
+				got := loadErr.Errors[i]
+				if got.Field != want.field || got.Line != want.line {
+					t.Errorf("error %d at line %d %q, want line %d %q", i, got.Line, got.Field, want.line, want.field)
+				}
+				if want.err != nil && !errors.Is(got, want.err) {
+					t.Errorf("error %d = %v, want %v", i, got, want.err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	script, err := LoadFile("../../cmd/first_steps/scripts/first_steps_1.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script.TragedySet != first_steps.SetID {
+		t.Errorf("tragedy set = %q, want %q", script.TragedySet, first_steps.SetID)
+	}
+	if _, err := LoadFile("missing.json", nil); err == nil {
+		t.Error("loading a missing file should fail")
+	}
+}
@@ -0,0 +1,60 @@
+package scripts
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"tragedy-looper/engine/internal/models"
+)
+
+// Export 将剧本转换为剧本文件格式，所有内容都以 ID 引用
+func Export(script *models.Script) *File {
+	file := &File{
+		Title:        script.Title,
+		TragedySet:   script.TragedySet,
+		MaxLoops:     script.MaxLoops,
+		DaysPerLoop:  script.DaysPerLoop,
+		SpecialRules: script.SpecialRules,
+	}
+	if script.MainPlot != nil {
+		file.MainPlot = script.MainPlot.ID
+	}
+	for _, plot := range script.SubPlots {
+		file.SubPlots = append(file.SubPlots, plot.ID)
+	}
+	for _, character := range script.Characters {
+		entry := CharacterEntry{Name: string(character.Name)}
+		if role := character.Role(); role != nil && role.Type != models.RolePersonType {
+			entry.Role = string(role.Type)
+		}
+		file.Characters = append(file.Characters, entry)
+	}
+	for _, scheduled := range script.Incidents {
+		file.Incidents = append(file.Incidents, IncidentEntry{
+			Type:    string(scheduled.Type()),
+			Day:     scheduled.Day,
+			Culprit: string(scheduled.Culprit),
+		})
+	}
+	return file
+}
+
+// Write 以剧本文件格式写出剧本
+func Write(w io.Writer, script *models.Script) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Export(script))
+}
+
+// WriteFile 将剧本写入文件
+func WriteFile(path string, script *models.Script) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, script); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
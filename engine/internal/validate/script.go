@@ -0,0 +1,198 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"tragedy-looper/engine/internal/models"
+)
+
+var (
+	// ErrOutOfRange 循环数或天数超出规则允许的范围
+	ErrOutOfRange = errors.New("out of range")
+	// ErrMissingPlot 缺少剧情或剧情类型错误
+	ErrMissingPlot = errors.New("invalid plot")
+	// ErrRoleCount 身份数量不在剧情允许的范围内
+	ErrRoleCount = errors.New("role count out of range")
+	// ErrUnexpectedRole 身份不是任何剧情要求的身份
+	ErrUnexpectedRole = errors.New("role not required by any plot")
+	// ErrInvalidCharacter 剧本没有角色或角色重复
+	ErrInvalidCharacter = errors.New("invalid character")
+	// ErrInvalidIncident 事件日期或当事人不合法
+	ErrInvalidIncident = errors.New("invalid incident")
+)
+
+// 规则允许的循环数和天数范围
+const (
+	MinLoops       = 1
+	MaxLoops       = 8
+	MinDaysPerLoop = 1
+	MaxDaysPerLoop = 8
+)
+
+// Violation 剧本违反的一条规则
+type Violation struct {
+	Field string // 字段路径，与剧本文件一致，如 characters[2].role
+	Err   error
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s: %v", v.Field, v.Err)
+}
+
+func (v *Violation) Unwrap() error {
+	return v.Err
+}
+
+// ScriptError 剧本验证发现的所有违规
+type ScriptError struct {
+	Violations []*Violation
+}
+
+func (e *ScriptError) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		lines = append(lines, v.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ScriptError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		errs = append(errs, v)
+	}
+	return errs
+}
+
+// Script 验证剧本，返回所有违规而不只是第一个，剧本合法时返回空
+func Script(script *models.Script) []*Violation {
+	c := &scriptChecker{script: script}
+	c.ranges()
+	c.plots()
+	c.characters()
+	c.roles()
+	c.incidents()
+	return c.violations
+}
+
+// ValidateScript 验证剧本，有违规时返回 *ScriptError
+func ValidateScript(script *models.Script) error {
+	if violations := Script(script); len(violations) > 0 {
+		return &ScriptError{Violations: violations}
+	}
+	return nil
+}
+
+// scriptChecker 收集剧本验证中的违规
+type scriptChecker struct {
+	script     *models.Script
+	names      map[models.CharacterName]bool // 剧本中的角色
+	violations []*Violation
+}
+
+func (c *scriptChecker) fail(field string, err error) {
+	c.violations = append(c.violations, &Violation{Field: field, Err: err})
+}
+
+// check 记录验证失败的结果
+func (c *scriptChecker) check(field string, sentinel error, result ValidationResult) {
+	if !result.Valid {
+		c.fail(field, fmt.Errorf("%w: %v", sentinel, result.Error))
+	}
+}
+
+// ranges 循环数和天数必须在规则范围内
+func (c *scriptChecker) ranges() {
+	c.check("maxLoops", ErrOutOfRange, InRange("maxLoops", c.script.MaxLoops, MinLoops, MaxLoops))
+	c.check("daysPerLoop", ErrOutOfRange, InRange("daysPerLoop", c.script.DaysPerLoop, MinDaysPerLoop, MaxDaysPerLoop))
+}
+
+// plots 必须有一个主剧情，子剧情类型正确且不重复
+func (c *scriptChecker) plots() {
+	if c.script.MainPlot == nil {
+		c.fail("mainPlot", fmt.Errorf("%w: main plot is required", ErrMissingPlot))
+	} else if c.script.MainPlot.Type != models.MainPlot {
+		c.fail("mainPlot", fmt.Errorf("%w: plot %q is a %s", ErrMissingPlot, c.script.MainPlot.ID, c.script.MainPlot.Type))
+	}
+	seen := make(map[string]bool)
+	for i, plot := range c.script.SubPlots {
+		field := fmt.Sprintf("subPlots[%d]", i)
+		switch {
+		case plot == nil:
+			c.fail(field, fmt.Errorf("%w: subplot is empty", ErrMissingPlot))
+		case plot.Type != models.SubPlot:
+			c.fail(field, fmt.Errorf("%w: plot %q is a %s", ErrMissingPlot, plot.ID, plot.Type))
+		case seen[plot.ID]:
+			c.fail(field, fmt.Errorf("%w: duplicate subplot %q", ErrMissingPlot, plot.ID))
+		default:
+			seen[plot.ID] = true
+		}
+	}
+}
+
+// characters 剧本中至少有一个角色，且角色不能重复
+func (c *scriptChecker) characters() {
+	c.names = make(map[models.CharacterName]bool)
+	if len(c.script.Characters) == 0 {
+		c.fail("characters", fmt.Errorf("%w: at least one character is required", ErrInvalidCharacter))
+	}
+	for i, character := range c.script.Characters {
+		if c.names[character.Name] {
+			c.fail(fmt.Sprintf("characters[%d].name", i), fmt.Errorf("%w: duplicate character %q", ErrInvalidCharacter, character.Name))
+		}
+		c.names[character.Name] = true
+	}
+}
+
+// roles 角色的身份数量必须在主剧情和子剧情允许的范围内，Person 不计数
+func (c *scriptChecker) roles() {
+	expected := c.script.RoleRanges()
+	actual := make(map[models.RoleType]int)
+	for i, character := range c.script.Characters {
+		roleType := models.RolePersonType
+		if character.Role() != nil {
+			roleType = character.Role().Type
+		}
+		if roleType == models.RolePersonType {
+			continue
+		}
+		if _, ok := expected[roleType]; !ok {
+			c.fail(fmt.Sprintf("characters[%d].role", i), fmt.Errorf("%w: %s (%s)", ErrUnexpectedRole, roleType, character.Name))
+			continue
+		}
+		actual[roleType]++
+	}
+
+	reported := make(map[models.RoleType]bool)
+	for _, plot := range c.script.Plots() {
+		for _, roleType := range plot.Roles {
+			// 多个剧情加入同一身份时只报告一次
+			if reported[roleType] {
+				continue
+			}
+			reported[roleType] = true
+			if want := expected[roleType]; !want.Contains(actual[roleType]) {
+				c.fail("characters", fmt.Errorf("%w: %s requires %s, script has %d", ErrRoleCount, roleType, want, actual[roleType]))
+			}
+		}
+	}
+}
+
+// incidents 事件日期在循环天数内且不重复，当事人必须是剧本中的角色
+func (c *scriptChecker) incidents() {
+	days := make(map[int]int)
+	for i, scheduled := range c.script.Incidents {
+		field := fmt.Sprintf("incidents[%d]", i)
+		if scheduled.Day < 1 || scheduled.Day > c.script.DaysPerLoop {
+			c.fail(field+".day", fmt.Errorf("%w: day %d is outside 1 to %d", ErrInvalidIncident, scheduled.Day, c.script.DaysPerLoop))
+		} else if first, ok := days[scheduled.Day]; ok {
+			c.fail(field+".day", fmt.Errorf("%w: day %d already has incidents[%d]", ErrInvalidIncident, scheduled.Day, first))
+		} else {
+			days[scheduled.Day] = i
+		}
+		if !c.names[scheduled.Culprit] {
+			c.fail(field+".culprit", fmt.Errorf("%w: culprit %q is not a character of the script", ErrInvalidIncident, scheduled.Culprit))
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+	"tragedy-looper/engine/cmd/first_steps"
+	"tragedy-looper/engine/internal/models"
+)
+
+// violationWant 期望的违规
+type violationWant struct {
+	field string
+	err   error
+}
+
+func TestScript(t *testing.T) {
+	tests := []struct {
+		name string
+		// modify 修改 First Steps 第一个剧本
+		modify func(script *models.Script)
+		want   []violationWant
+	}{
+		{name: "valid script", modify: func(script *models.Script) {}},
+		{
+			name: "loop and day counts out of range",
+			modify: func(script *models.Script) {
+				script.MaxLoops = MinLoops - 1
+				script.DaysPerLoop = MaxDaysPerLoop + 1
+			},
+			want: []violationWant{{"maxLoops", ErrOutOfRange}, {"daysPerLoop", ErrOutOfRange}},
+		},
+		{
+			name: "missing main plot",
+			modify: func(script *models.Script) {
+				script.MainPlot = nil
+				// 去掉只由主剧情要求的身份，只留下缺少主剧情的违规
+				script.Characters[1].SetRole(first_steps.NewRole(models.RolePersonType))
+				script.Characters[4].SetRole(first_steps.NewRole(models.RolePersonType))
+				script.Characters[5].SetRole(first_steps.NewRole(models.RolePersonType))
+			},
+			want: []violationWant{{"mainPlot", ErrMissingPlot}},
+		},
+		{
+			name: "duplicate subplot",
+			modify: func(script *models.Script) {
+				script.SubPlots = append(script.SubPlots, first_steps.ShadowOfTheRipper)
+			},
+			// 重复的子剧情仍然计入身份数量
+			want: []violationWant{
+				{"subPlots[1]", ErrMissingPlot},
+				{"characters", ErrRoleCount},
+				{"characters", ErrRoleCount},
+			},
+		},
+		{
+			name: "main plot as subplot",
+			modify: func(script *models.Script) {
+				script.SubPlots[0] = first_steps.MurderPlan
+				script.Characters[2].SetRole(first_steps.NewRole(models.RolePersonType))
+				script.Characters[3].SetRole(first_steps.NewRole(models.RolePersonType))
+			},
+			want: []violationWant{
+				{"subPlots[0]", ErrMissingPlot},
+				{"characters", ErrRoleCount},
+				{"characters", ErrRoleCount},
+				{"characters", ErrRoleCount},
+			},
+		},
+		{
+			name: "no characters",
+			modify: func(script *models.Script) {
+				script.Characters = nil
+				script.Incidents = nil
+			},
+			want: []violationWant{
+				{"characters", ErrInvalidCharacter},
+				{"characters", ErrRoleCount},
+				{"characters", ErrRoleCount},
+				{"characters", ErrRoleCount},
+				{"characters", ErrRoleCount},
+				{"characters", ErrRoleCount},
+			},
+		},
+		{
+			name: "duplicate character",
+			modify: func(script *models.Script) {
+				script.Characters = append(script.Characters, first_steps.NewBoyStudent(first_steps.NewRole(models.RolePersonType)))
+			},
+			want: []violationWant{{"characters[6].name", ErrInvalidCharacter}},
+		},
+		{
+			name: "role not required by any plot",
+			modify: func(script *models.Script) {
+				script.Characters[0].SetRole(first_steps.NewRole(first_steps.Cultist))
+			},
+			want: []violationWant{{"characters[0].role", ErrUnexpectedRole}},
+		},
+		{
+			name: "required role missing",
+			modify: func(script *models.Script) {
+				script.Characters[4].SetRole(first_steps.NewRole(models.RolePersonType))
+			},
+			want: []violationWant{{"characters", ErrRoleCount}},
+		},
+		{
+			name: "required role repeated",
+			modify: func(script *models.Script) {
+				script.Characters[0].SetRole(first_steps.NewRole(first_steps.Killer))
+			},
+			want: []violationWant{{"characters", ErrRoleCount}},
+		},
+		{
+			name: "incident outside the loop",
+			modify: func(script *models.Script) {
+				script.Incidents[1].Day = script.DaysPerLoop + 1
+			},
+			want: []violationWant{{"incidents[1].day", ErrInvalidIncident}},
+		},
+		{
+			name: "two incidents on one day",
+			modify: func(script *models.Script) {
+				script.Incidents[1].Day = script.Incidents[0].Day
+			},
+			want: []violationWant{{"incidents[1].day", ErrInvalidIncident}},
+		},
+		{
+			name: "culprit not in the script",
+			modify: func(script *models.Script) {
+				script.Incidents[0].Culprit = "Alien"
+			},
+			want: []violationWant{{"incidents[0].culprit", ErrInvalidIncident}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := first_steps.NewFirstSteps1()
+			tt.modify(script)
+
+			violations := Script(script)
+			if len(violations) != len(tt.want) {
+				t.Fatalf("got %d violations, want %d:\n%v", len(violations), len(tt.want), &ScriptError{Violations: violations})
+			}
+			for i, want := range tt.want {
+				if violations[i].Field != want.field || !errors.Is(violations[i], want.err) {
+					t.Errorf("violation %d = %v, want %s: %v", i, violations[i], want.field, want.err)
+				}
+			}
+
+			err := ValidateScript(script)
+			var scriptErr *ScriptError
+			switch {
+			case len(tt.want) == 0 && err != nil:
+				t.Errorf("ValidateScript err = %v", err)
+			case len(tt.want) > 0 && (!errors.As(err, &scriptErr) || !errors.Is(err, tt.want[0].err)):
+				t.Errorf("ValidateScript err = %v, want a *ScriptError wrapping %v", err, tt.want[0].err)
+			}
+		})
+	}
+}